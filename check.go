@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/xxlv/ai-create-mcp/internal/manifest"
+)
+
+// exitDrift is the exit status of check when the project or its inputs
+// changed since generation, distinct from 1 for usage or read errors.
+const exitDrift = 2
+
+// runCheck implements `ai-create-mcp check [project dir]`.
+func runCheck(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ai-create-mcp check [project dir]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 1
+	}
+	dir := "."
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+
+	m, err := manifest.Read(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		return 1
+	}
+	edited, err := m.Drift(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		return 1
+	}
+	inputs, err := m.ChangedInputs()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		return 1
+	}
+
+	printCheck(os.Stdout, edited, inputs)
+	if len(edited) > 0 || len(inputs) > 0 {
+		return exitDrift
+	}
+	return 0
+}
+
+func printCheck(w io.Writer, edited, inputs []string) {
+	if len(edited) == 0 && len(inputs) == 0 {
+		fmt.Fprintln(w, "✅ The project matches its manifest")
+		return
+	}
+	for _, rel := range edited {
+		fmt.Fprintf(w, "❌ %s was edited or deleted since generation\n", rel)
+	}
+	for _, path := range inputs {
+		fmt.Fprintf(w, "❌ %s changed since generation, regenerate the project\n", path)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/xxlv/ai-create-mcp/internal/adapters/oas/oas31"
+	"github.com/xxlv/ai-create-mcp/internal/specdiff"
+)
+
+// exitBreaking is the exit status of diff when breaking changes were found,
+// distinct from 1 so CI can tell them apart from usage or load errors.
+const exitBreaking = 2
+
+// runDiff implements `ai-create-mcp diff [-json] <old spec> <new spec>`.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "Print the report as JSON")
+	var overlays stringList
+	fs.Var(&overlays, "overlay", "OpenAPI Overlay applied to both specs before comparing, repeatable")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ai-create-mcp diff [-overlay file]... [-json] <old oas path> <new oas path>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 1
+	}
+
+	prev, err := oas31.New(fs.Arg(0), oas31.WithOverlays(overlays...)).ToTemplateData()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error loading %s: %v\n", fs.Arg(0), err)
+		return 1
+	}
+	next, err := oas31.New(fs.Arg(1), oas31.WithOverlays(overlays...)).ToTemplateData()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error loading %s: %v\n", fs.Arg(1), err)
+		return 1
+	}
+
+	report := specdiff.Compare(prev, next)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			return 1
+		}
+	} else {
+		printDiff(os.Stdout, report)
+	}
+	if report.Breaking {
+		return exitBreaking
+	}
+	return 0
+}
+
+func printDiff(w io.Writer, report *specdiff.Report) {
+	if len(report.Changes) == 0 {
+		fmt.Fprintln(w, "✅ No changes to the MCP tools")
+		return
+	}
+	breaking := 0
+	for _, change := range report.Changes {
+		marker := "  "
+		if change.Breaking {
+			marker = "❌"
+			breaking++
+		}
+		fmt.Fprintf(w, "%s %s\n", marker, change)
+	}
+	fmt.Fprintf(w, "\n%d change(s), %d breaking\n", len(report.Changes), breaking)
+}
@@ -7,6 +7,7 @@ toolchain go1.22.9
 require (
 	github.com/Masterminds/semver/v3 v3.3.1
 	github.com/getkin/kin-openapi v0.129.0
+	github.com/oasdiff/yaml v0.0.0-20241210131133-6b86fb107d80
 	github.com/pelletier/go-toml v1.9.5
 	github.com/stretchr/testify v1.9.0
 )
@@ -18,7 +19,6 @@ require (
 	github.com/josharian/intern v1.0.0 // indirect
 	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826 // indirect
-	github.com/oasdiff/yaml v0.0.0-20241210131133-6b86fb107d80 // indirect
 	github.com/oasdiff/yaml3 v0.0.0-20241210130736-a94c01f36349 // indirect
 	github.com/perimeterx/marshmallow v1.1.5 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
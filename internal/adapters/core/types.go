@@ -1,5 +1,13 @@
 package core
 
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
 type TemplateData struct {
 	MissBaseURL       bool // when openapi miss server set True
 	BinaryName        string
@@ -11,6 +19,162 @@ type TemplateData struct {
 	Tools             []Tool
 	ServerDescription string
 	ServerDirectory   string
+	InstallCommand    string // shell command that installs the project's dependencies
+	RunCommand        string // shell command that starts the server from its directory
+	Sources           []Source
+	Upstreams         []Upstream
+	MaxBinarySize     int64    // largest image or binary response returned to the client, in bytes
+	MaxResponseSize   int64    // largest text response returned to the client, in bytes
+	ToolMode          string   // ToolModeStatic or ToolModeDynamic
+	Lookups           []Lookup // prompt argument and resource template variable completions listed by calling a tool
+	Timeout           float64  // seconds an upstream request may take
+	Retries           int      // retries of a failed upstream request
+	RetryBackoff      float64  // seconds before the first retry, doubled for each further one
+	// AllowedHosts are the hosts the generated server may send requests to,
+	// such as api.example.com or *.example.com. When empty, those of the
+	// upstream base URLs.
+	AllowedHosts []string
+}
+
+// DefaultMaxBinarySize is the MaxBinarySize of generated servers unless
+// configured otherwise.
+const DefaultMaxBinarySize = 5 << 20
+
+// DefaultMaxResponseSize is the MaxResponseSize of generated servers unless
+// configured otherwise.
+const DefaultMaxResponseSize = 100 << 10
+
+// Default upstream request policy of generated servers.
+const (
+	DefaultTimeout      = 30
+	DefaultRetries      = 2
+	DefaultRetryBackoff = 0.5
+)
+
+const (
+	// ToolModeStatic lists every operation as its own tool.
+	ToolModeStatic = "static"
+	// ToolModeDynamic lists only the search_operations, describe_operation
+	// and invoke_operation meta-tools, keeping large APIs out of the
+	// model's context until an operation is needed.
+	ToolModeDynamic = "dynamic"
+)
+
+// ValidToolMode reports whether mode is a known ToolMode.
+func ValidToolMode(mode string) bool {
+	return mode == ToolModeStatic || mode == ToolModeDynamic
+}
+
+// Upstream is an API the generated server forwards tool calls to. Specs
+// merged into one server each get their own.
+type Upstream struct {
+	Name        string // identifier used for routing, server flags and environment variables
+	Endpoints   []string
+	MissBaseURL bool // no server in the spec, the base URL is passed at startup
+	Auth        Auth
+	RateLimit   RateLimit // shared by all tools of the upstream
+}
+
+// RateLimit caps the requests a generated server sends. Zero fields are
+// unlimited.
+type RateLimit struct {
+	Rate        float64 // requests per second
+	Burst       int     // requests sent at once before Rate applies, at least 1
+	Concurrency int     // requests in flight at the same time
+}
+
+// Set reports whether the limit restricts anything.
+func (r RateLimit) Set() bool {
+	return r.Rate > 0 || r.Concurrency > 0
+}
+
+// Merge fills the zero fields of r from fallback.
+func (r RateLimit) Merge(fallback RateLimit) RateLimit {
+	if r.Rate == 0 {
+		r.Rate = fallback.Rate
+	}
+	if r.Burst == 0 {
+		r.Burst = fallback.Burst
+	}
+	if r.Concurrency == 0 {
+		r.Concurrency = fallback.Concurrency
+	}
+	return r
+}
+
+var rateFormat = regexp.MustCompile(`^\s*([0-9]+(?:\.[0-9]+)?)\s*/\s*(s|m|h)\s*$`)
+
+// ParseRate parses a rate such as "10/s", "100/m" or "1000/h" into
+// requests per second.
+func ParseRate(rate string) (float64, error) {
+	match := rateFormat.FindStringSubmatch(rate)
+	if match == nil {
+		return 0, fmt.Errorf("invalid rate %q, expected requests per second, minute or hour such as 10/s", rate)
+	}
+	n, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate %q: %v", rate, err)
+	}
+	switch match[2] {
+	case "m":
+		n /= 60
+	case "h":
+		n /= 3600
+	}
+	return n, nil
+}
+
+// ValidHostPattern reports whether pattern is a host name, optionally
+// starting with a *. wildcard for its subdomains.
+func ValidHostPattern(pattern string) bool {
+	host := strings.TrimPrefix(pattern, "*.")
+	return host != "" && !strings.ContainsAny(host, "*/:@?#[] ")
+}
+
+// HostAllowed reports whether host matches one of patterns. *.example.com
+// matches the subdomains of example.com, not example.com itself.
+func HostAllowed(patterns []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// Auth describes how the generated server presents a credential upstream.
+type Auth struct {
+	Type string // bearer, header, query or none
+	Name string // header or query parameter carrying the credential
+	Env  string // environment variable holding the credential
+	// TokenCommand is a shell command printing the credential, run by the
+	// generated server when neither the environment nor its dotenv file
+	// sets Env.
+	TokenCommand string
+}
+
+// Auth types.
+const (
+	AuthBearer = "bearer"
+	AuthHeader = "header"
+	AuthQuery  = "query"
+	AuthNone   = "none"
+)
+
+// DefaultUpstream is the name of the upstream of a single converted spec.
+const DefaultUpstream = "api"
+
+// Source records the document a TemplateData was converted from.
+type Source struct {
+	Type     string // adapter source type, e.g. "oas31"
+	Location string // file path or URL as given by the user
+	Digest   string // hex encoded sha256 of the raw document
 }
 
 type Resource struct {
@@ -18,24 +182,211 @@ type Resource struct {
 	Description string
 	URI         string
 	MimeType    string
+	// Arguments are the path parameters of the operation. A resource with
+	// any is a resource template, URI holding a {variable} for each.
+	Arguments []Argument
+}
+
+// IsTemplate reports whether the resource is a URI template, listed with
+// resources/templates/list rather than resources/list.
+func (r Resource) IsTemplate() bool {
+	return len(r.Arguments) > 0
+}
+
+// ResourceTemplates returns the resources that are URI templates.
+func (d TemplateData) ResourceTemplates() []Resource {
+	var templates []Resource
+	for _, resource := range d.Resources {
+		if resource.IsTemplate() {
+			templates = append(templates, resource)
+		}
+	}
+	return templates
+}
+
+// Lookup completes a prompt argument or resource template variable with
+// values listed by calling a tool, for values the spec cannot enumerate such
+// as IDs.
+type Lookup struct {
+	Argument string // name of the completed prompt argument or template variable
+	Tool     string
+	// Arguments are passed to Tool. In strings, {value} stands for the text
+	// typed so far and {name} for prompt arguments already filled in.
+	Arguments map[string]any
+	Items     string // dotted path of the list in the response, empty for the response itself
+	Field     string // dotted path of the value in each item, empty for the item itself
 }
 
 type Prompt struct {
 	Name        string
 	Description string
 	Arguments   []Argument
+	Tool        string // name of the tool the prompt asks the model to call
+	// Template is the text of the prompt message, with {name} placeholders
+	// for the arguments and {tool} for the tool name. When empty the
+	// generated server asks the model to call Tool with the arguments given.
+	Template string
+	// Auto is set on prompts generated for every GET operation, as opposed
+	// to those asked for with x-mcp-prompt or the config file.
+	Auto bool
+}
+
+var placeholder = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+
+// CheckTemplate reports placeholders of the template that are neither an
+// argument of the prompt nor {tool}.
+func (p Prompt) CheckTemplate() error {
+	for _, match := range placeholder.FindAllStringSubmatch(p.Template, -1) {
+		name := match[1]
+		if name == "tool" {
+			continue
+		}
+		known := false
+		for _, arg := range p.Arguments {
+			known = known || arg.Name == name
+		}
+		if !known {
+			return fmt.Errorf("prompt %s: template uses {%s}, which is not one of its arguments", p.Name, name)
+		}
+	}
+	return nil
 }
 
 type Argument struct {
 	Name        string
 	Description string
 	Required    bool
+	Type        string // JSON Schema type, empty when the spec does not declare one
+	In          string // where the value is sent upstream: path, query, header, cookie or body
+	WireName    string // name sent upstream; Name is the one shown to the model
+	Binary      bool   // file content, passed as base64 or as a local file path
+	// Schema is the JSON Schema of the value, with recursive schemas
+	// referring to the $defs of the tool input schema.
+	Schema   map[string]any
+	Enum     []any // allowed values, or allowed items of an array
+	Examples []any // example values given by the spec
+}
+
+// Completions returns the values offered when a client completes the
+// argument: the enum first, then the examples, rendered as the strings a
+// user would type. Objects and arrays are left out.
+func (a Argument) Completions() []string {
+	var values []string
+	seen := make(map[string]bool)
+	for _, value := range append(append([]any(nil), a.Enum...), a.Examples...) {
+		var text string
+		switch v := value.(type) {
+		case nil, map[string]any, []any:
+			continue
+		case string:
+			text = v
+		default:
+			raw, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			text = string(raw)
+		}
+		if !seen[text] {
+			seen[text] = true
+			values = append(values, text)
+		}
+	}
+	return values
 }
 
+const (
+	// InBody marks an Argument taken from a request body property.
+	InBody = "body"
+	// InRawBody marks an Argument holding the whole request body, used
+	// when the body is not an object.
+	InRawBody = "rawbody"
+	// InFields marks the argument projecting the response onto some of its
+	// fields, which is not sent upstream.
+	InFields = "fields"
+)
+
 type Tool struct {
 	Name        string
 	Description string
 	Arguments   []Argument
 	Method      string
 	Path        string
+	Annotations ToolAnnotations
+	Upstream    string // name of the Upstream the tool calls
+	Tags        []string
+	// InputSchema is the JSON Schema of the tool arguments, built from the
+	// Schema of every argument.
+	InputSchema map[string]any
+	// OutputSchema is the JSON Schema of the structured result, nil when
+	// the successful responses are not all JSON.
+	OutputSchema map[string]any
+	// WrapOutput is set when the response is not a JSON object and is
+	// returned as {"result": <response>} to fit OutputSchema.
+	WrapOutput bool
+	// ResponseTypes are the content types of the successful responses,
+	// preferred ones first.
+	ResponseTypes []string
+	// RequestType is the content type the request body is sent as, chosen
+	// among RequestTypes, all those the spec declares.
+	RequestType  string
+	RequestTypes []string
+	// Timeout is the number of seconds a request of this tool may take, 0
+	// for the server's default.
+	Timeout float64
+	// RateLimit applies to the requests of this tool, on top of the limit
+	// of its upstream.
+	RateLimit RateLimit
+	// Pagination is how the operation pages its results, nil when it takes
+	// no recognized paging arguments.
+	Pagination *Pagination
+	// Confirm sets whether the generated server asks the user to approve
+	// each request before sending it, set by x-mcp-confirm or the config.
+	// Nil follows the destructive hint, see NeedsConfirmation.
+	Confirm *bool
+}
+
+// Pagination describes the paging arguments of an operation, so the
+// generated server can offer the arguments of the next page.
+type Pagination struct {
+	Style    string `json:"style"`    // PageByNumber, PageByOffset or PageByCursor
+	Argument string `json:"argument"` // argument selecting the page
+	Size     string `json:"size"`     // argument limiting the page size, empty when there is none
+	Start    int    `json:"start"`    // first page number, or 0 for offsets
+}
+
+// Pagination styles.
+const (
+	PageByNumber = "page"
+	PageByOffset = "offset"
+	PageByCursor = "cursor"
+)
+
+// Retryable reports whether a failed request can be sent again without
+// risking a repeated side effect, judged by the tool annotations.
+func (t Tool) Retryable() bool {
+	return isTrue(t.Annotations.ReadOnlyHint) || isTrue(t.Annotations.IdempotentHint)
+}
+
+// NeedsConfirmation reports whether the user approves each request of the
+// tool: as set explicitly, or else when the tool is destructive.
+func (t Tool) NeedsConfirmation() bool {
+	if t.Confirm != nil {
+		return *t.Confirm
+	}
+	return isTrue(t.Annotations.DestructiveHint)
+}
+
+func isTrue(hint *bool) bool {
+	return hint != nil && *hint
+}
+
+// ToolAnnotations are the MCP behavior hints of a tool. A nil hint is left
+// out so the client applies the protocol default.
+type ToolAnnotations struct {
+	Title           string
+	ReadOnlyHint    *bool
+	DestructiveHint *bool
+	IdempotentHint  *bool
+	OpenWorldHint   *bool
 }
@@ -0,0 +1,288 @@
+// Package merge combines the conversion results of several specs into the
+// template data of a single MCP server that routes every tool to the API it
+// came from.
+package merge
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+// Source is one spec merged into the server.
+type Source struct {
+	// Name identifies the upstream in the generated server, its flags and
+	// environment variables. Derived from the spec location when empty.
+	Name string
+	// Prefix is prepended to the names of the tools, prompts and resources
+	// of the source.
+	Prefix string
+	// BaseURL replaces the servers declared by the spec.
+	BaseURL string
+	// Auth overrides how the credential is sent. Unset fields keep the
+	// defaults: a bearer token read from <NAME>_TOKEN.
+	Auth *core.Auth
+	// RateLimit overrides the limits set by the spec, field by field.
+	RateLimit *core.RateLimit
+	Adapter   core.Adapter
+}
+
+type Adapter struct {
+	sources []Source
+}
+
+func New(sources ...Source) *Adapter {
+	return &Adapter{sources: sources}
+}
+
+var validName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+func (a *Adapter) ToTemplateData() (*core.TemplateData, error) {
+	if len(a.sources) == 0 {
+		return nil, fmt.Errorf("no spec to merge")
+	}
+	merged := &core.TemplateData{}
+	names := make(map[string]bool)
+	var titles []string
+	// owner records the source index of every merged tool, prompt and resource
+	var toolOwner, promptOwner, resourceOwner []int
+
+	for i, src := range a.sources {
+		data, err := src.Adapter.ToTemplateData()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load spec %d: %v", i+1, err)
+		}
+		if len(data.Upstreams) != 1 {
+			return nil, fmt.Errorf("spec %d: expected a single upstream, got %d", i+1, len(data.Upstreams))
+		}
+
+		name := src.Name
+		if name == "" {
+			name = uniqueName(nameFromSources(data.Sources), names)
+		}
+		if !validName.MatchString(name) {
+			return nil, fmt.Errorf("invalid source name %q: use lower case letters, digits and _", name)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("source name %q is used more than once", name)
+		}
+		names[name] = true
+		a.sources[i].Name = name
+
+		upstream := data.Upstreams[0]
+		upstream.Name = name
+		if src.BaseURL != "" {
+			upstream.Endpoints = []string{src.BaseURL}
+			upstream.MissBaseURL = false
+		}
+		upstream.Auth = core.Auth{Type: core.AuthBearer, Name: "Authorization", Env: strings.ToUpper(name) + "_TOKEN"}
+		if err := overrideAuth(&upstream.Auth, src.Auth); err != nil {
+			return nil, fmt.Errorf("source %s: %v", name, err)
+		}
+		if src.RateLimit != nil {
+			upstream.RateLimit = src.RateLimit.Merge(upstream.RateLimit)
+		}
+		merged.Upstreams = append(merged.Upstreams, upstream)
+
+		for _, tool := range data.Tools {
+			tool.Name = qualify(src.Prefix, tool.Name)
+			tool.Upstream = name
+			merged.Tools = append(merged.Tools, tool)
+			toolOwner = append(toolOwner, i)
+		}
+		for _, prompt := range data.Prompts {
+			prompt.Name = qualify(src.Prefix, prompt.Name)
+			prompt.Tool = qualify(src.Prefix, prompt.Tool)
+			merged.Prompts = append(merged.Prompts, prompt)
+			promptOwner = append(promptOwner, i)
+		}
+		for _, resource := range data.Resources {
+			resource.Name = qualify(src.Prefix, resource.Name)
+			resource.URI = strings.Replace(resource.URI, "://internal/", "://"+name+"/", 1)
+			merged.Resources = append(merged.Resources, resource)
+			resourceOwner = append(resourceOwner, i)
+		}
+
+		merged.Sources = append(merged.Sources, data.Sources...)
+		titles = append(titles, data.ServerName)
+		if i == 0 {
+			merged.ServerVersion = data.ServerVersion
+			merged.Endpoints = upstream.Endpoints
+			merged.MissBaseURL = upstream.MissBaseURL
+		}
+	}
+	merged.ServerName = strings.Join(titles, " + ")
+
+	toolNames := make([]*string, len(merged.Tools))
+	for i := range merged.Tools {
+		toolNames[i] = &merged.Tools[i].Name
+	}
+	promptNames := make([]*string, len(merged.Prompts))
+	for i := range merged.Prompts {
+		promptNames[i] = &merged.Prompts[i].Name
+	}
+	resourceNames := make([]*string, len(merged.Resources))
+	for i := range merged.Resources {
+		resourceNames[i] = &merged.Resources[i].Name
+	}
+	// prompts follow their tool when resolve renames it
+	type ownedName struct {
+		owner int
+		name  string
+	}
+	renamed := make(map[ownedName]*string, len(merged.Tools))
+	for i, name := range toolNames {
+		renamed[ownedName{toolOwner[i], *name}] = name
+	}
+	if err := a.resolve("tool", toolNames, toolOwner); err != nil {
+		return nil, err
+	}
+	for i := range merged.Prompts {
+		prompt := &merged.Prompts[i]
+		if name, ok := renamed[ownedName{promptOwner[i], prompt.Tool}]; ok {
+			prompt.Tool = *name
+		}
+	}
+	if err := a.resolve("prompt", promptNames, promptOwner); err != nil {
+		return nil, err
+	}
+	if err := a.resolve("resource", resourceNames, resourceOwner); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+func (a *Adapter) GetSourceType() string {
+	return "merge"
+}
+
+// resolve makes the names of one kind unique across sources. A name shared
+// by several sources is qualified with the name of every source that has no
+// prefix of its own; whatever still clashes is an error.
+func (a *Adapter) resolve(kind string, names []*string, owner []int) error {
+	clashes := duplicates(names)
+	for _, name := range sortedKeys(clashes) {
+		for _, i := range clashes[name] {
+			src := a.sources[owner[i]]
+			if src.Prefix != "" {
+				continue
+			}
+			qualified := qualify(src.Name, *names[i])
+			fmt.Fprintf(os.Stderr, "WARN: %s %s is defined by several specs, exposing the one from %s as %s\n", kind, *names[i], src.Name, qualified)
+			*names[i] = qualified
+		}
+	}
+	clashes = duplicates(names)
+	for _, name := range sortedKeys(clashes) {
+		var sources []string
+		for _, i := range clashes[name] {
+			sources = append(sources, a.sources[owner[i]].Name)
+		}
+		return fmt.Errorf("%s %s is defined by sources %s; give them distinct prefixes", kind, name, strings.Join(sources, ", "))
+	}
+	return nil
+}
+
+// duplicates maps every name used more than once to the indexes using it.
+func duplicates(names []*string) map[string][]int {
+	seen := make(map[string][]int)
+	for i, name := range names {
+		seen[*name] = append(seen[*name], i)
+	}
+	for name, idxs := range seen {
+		if len(idxs) < 2 {
+			delete(seen, name)
+		}
+	}
+	return seen
+}
+
+// qualify prepends prefix to name, separated by an underscore unless the
+// prefix already ends in a separator.
+func qualify(prefix, name string) string {
+	if prefix == "" || strings.HasSuffix(prefix, "_") || strings.HasSuffix(prefix, "-") {
+		return prefix + name
+	}
+	return prefix + "_" + name
+}
+
+func overrideAuth(auth *core.Auth, override *core.Auth) error {
+	if override == nil {
+		return nil
+	}
+	if override.Type != "" {
+		auth.Type = override.Type
+	}
+	if override.Name != "" {
+		auth.Name = override.Name
+	} else if auth.Type == core.AuthQuery {
+		auth.Name = ""
+	}
+	if override.Env != "" {
+		auth.Env = override.Env
+	}
+	if override.TokenCommand != "" {
+		auth.TokenCommand = override.TokenCommand
+	}
+	switch auth.Type {
+	case core.AuthNone:
+		if auth.TokenCommand != "" {
+			return fmt.Errorf("auth of type none takes no token_command")
+		}
+	case core.AuthBearer:
+	case core.AuthHeader, core.AuthQuery:
+		if auth.Name == "" {
+			return fmt.Errorf("%s auth needs the name of the %s parameter", auth.Type, auth.Type)
+		}
+	default:
+		return fmt.Errorf("unknown auth type %q, expected bearer, header, query or none", auth.Type)
+	}
+	return nil
+}
+
+// nameFromSources derives a source name from the file name of the spec.
+func nameFromSources(sources []core.Source) string {
+	if len(sources) == 0 {
+		return "api"
+	}
+	base := path.Base(strings.ReplaceAll(sources[0].Location, "\\", "/"))
+	base = strings.TrimSuffix(base, path.Ext(base))
+	var b strings.Builder
+	for _, r := range strings.ToLower(base) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	name := strings.Trim(b.String(), "_")
+	if name == "" || name[0] < 'a' || name[0] > 'z' {
+		name = "api_" + name
+	}
+	return strings.TrimRight(name, "_")
+}
+
+func uniqueName(name string, taken map[string]bool) string {
+	candidate := name
+	for n := 2; taken[candidate]; n++ {
+		candidate = fmt.Sprintf("%s_%d", name, n)
+	}
+	return candidate
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var _ core.Adapter = new(Adapter)
@@ -0,0 +1,195 @@
+package merge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+// static is an adapter returning fixed template data.
+type static core.TemplateData
+
+func (s *static) ToTemplateData() (*core.TemplateData, error) {
+	data := core.TemplateData(*s)
+	data.Tools = append([]core.Tool(nil), s.Tools...)
+	data.Resources = append([]core.Resource(nil), s.Resources...)
+	data.Prompts = append([]core.Prompt(nil), s.Prompts...)
+	return &data, nil
+}
+
+func (s *static) GetSourceType() string { return "static" }
+
+func spec(location, title string, endpoints []string, tools ...string) *static {
+	data := &static{
+		ServerName:    title,
+		ServerVersion: "1.0.0",
+		Sources:       []core.Source{{Type: "oas31", Location: location}},
+		Upstreams: []core.Upstream{{
+			Name:        core.DefaultUpstream,
+			Endpoints:   endpoints,
+			MissBaseURL: len(endpoints) == 0,
+			Auth:        core.Auth{Type: core.AuthBearer, Name: "Authorization", Env: "TOKEN"},
+		}},
+	}
+	for _, tool := range tools {
+		data.Tools = append(data.Tools, core.Tool{Name: tool, Upstream: core.DefaultUpstream})
+		data.Resources = append(data.Resources, core.Resource{Name: "Resource_" + tool, URI: "ai-create-mcp://internal/" + tool})
+		data.Prompts = append(data.Prompts, core.Prompt{Name: tool, Tool: tool})
+	}
+	return data
+}
+
+func toolNames(data *core.TemplateData) map[string]string {
+	names := make(map[string]string)
+	for _, tool := range data.Tools {
+		names[tool.Name] = tool.Upstream
+	}
+	return names
+}
+
+func TestMerge(t *testing.T) {
+	orders := spec("orders.yaml", "Orders", nil, "get_orders", "get_status")
+	orders.Upstreams[0].RateLimit = core.RateLimit{Rate: 5, Concurrency: 3}
+	data, err := New(
+		Source{Adapter: spec("specs/Pet-Store.v3.yaml", "Pets", []string{"https://pets.example.com"}, "get_pets", "get_status")},
+		Source{
+			Name:      "orders",
+			BaseURL:   "https://orders.internal",
+			Auth:      &core.Auth{Type: core.AuthHeader, Name: "X-API-Key", TokenCommand: "vault read -field=key secret/orders"},
+			RateLimit: &core.RateLimit{Rate: 1},
+			Adapter:   orders,
+		},
+		Source{
+			Prefix:  "billing",
+			Auth:    &core.Auth{Type: core.AuthNone},
+			Adapter: spec("billing.json", "Billing", nil, "get_status"),
+		},
+	).ToTemplateData()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Pets + Orders + Billing", data.ServerName)
+	assert.Equal(t, map[string]string{
+		"get_pets":                "pet_store_v3",
+		"pet_store_v3_get_status": "pet_store_v3",
+		"get_orders":              "orders",
+		"orders_get_status":       "orders",
+		"billing_get_status":      "billing",
+	}, toolNames(data))
+
+	assert.Equal(t, []core.Upstream{
+		{
+			Name:      "pet_store_v3",
+			Endpoints: []string{"https://pets.example.com"},
+			Auth:      core.Auth{Type: core.AuthBearer, Name: "Authorization", Env: "PET_STORE_V3_TOKEN"},
+		},
+		{
+			Name:      "orders",
+			Endpoints: []string{"https://orders.internal"},
+			Auth:      core.Auth{Type: core.AuthHeader, Name: "X-API-Key", Env: "ORDERS_TOKEN", TokenCommand: "vault read -field=key secret/orders"},
+			RateLimit: core.RateLimit{Rate: 1, Concurrency: 3},
+		},
+		{
+			Name:        "billing",
+			MissBaseURL: true,
+			Auth:        core.Auth{Type: core.AuthNone, Name: "Authorization", Env: "BILLING_TOKEN"},
+		},
+	}, data.Upstreams)
+
+	var uris []string
+	for _, resource := range data.Resources {
+		uris = append(uris, resource.URI)
+	}
+	assert.Contains(t, uris, "ai-create-mcp://orders/get_status")
+	assert.Contains(t, uris, "ai-create-mcp://billing/get_status")
+
+	prompts := make(map[string]string)
+	for _, prompt := range data.Prompts {
+		prompts[prompt.Name] = prompt.Tool
+	}
+	assert.Equal(t, map[string]string{
+		"get_pets":                "get_pets",
+		"pet_store_v3_get_status": "pet_store_v3_get_status",
+		"get_orders":              "get_orders",
+		"orders_get_status":       "orders_get_status",
+		"billing_get_status":      "billing_get_status",
+	}, prompts)
+	assert.Len(t, data.Sources, 3)
+}
+
+func TestMergeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []Source
+		want    string
+	}{
+		{
+			name:    "no sources",
+			sources: nil,
+			want:    "no spec to merge",
+		},
+		{
+			name: "duplicate names",
+			sources: []Source{
+				{Name: "api", Adapter: spec("a.yaml", "A", nil, "a")},
+				{Name: "api", Adapter: spec("b.yaml", "B", nil, "b")},
+			},
+			want: `source name "api" is used more than once`,
+		},
+		{
+			name: "invalid name",
+			sources: []Source{
+				{Name: "Pet Store", Adapter: spec("a.yaml", "A", nil, "a")},
+			},
+			want: "invalid source name",
+		},
+		{
+			name: "same prefix",
+			sources: []Source{
+				{Prefix: "x", Adapter: spec("a.yaml", "A", nil, "get_status")},
+				{Prefix: "x_", Adapter: spec("b.yaml", "B", nil, "get_status")},
+			},
+			want: "tool x_get_status is defined by sources a, b; give them distinct prefixes",
+		},
+		{
+			name: "query auth without name",
+			sources: []Source{
+				{Auth: &core.Auth{Type: core.AuthQuery}, Adapter: spec("a.yaml", "A", nil, "a")},
+			},
+			want: "query auth needs the name of the query parameter",
+		},
+		{
+			name: "unknown auth",
+			sources: []Source{
+				{Auth: &core.Auth{Type: "oauth"}, Adapter: spec("a.yaml", "A", nil, "a")},
+			},
+			want: `unknown auth type "oauth"`,
+		},
+		{
+			name: "token command without auth",
+			sources: []Source{
+				{Auth: &core.Auth{Type: core.AuthNone, TokenCommand: "cat token"}, Adapter: spec("a.yaml", "A", nil, "a")},
+			},
+			want: "auth of type none takes no token_command",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.sources...).ToTemplateData()
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestNameFromSources(t *testing.T) {
+	tests := map[string]string{
+		"pets.yaml":                           "pets",
+		"https://example.com/v2/openapi.json": "openapi",
+		"specs/Order-Service.v1.yml":          "order_service_v1",
+		"3d.yaml":                             "api_3d",
+	}
+	for location, want := range tests {
+		assert.Equal(t, want, nameFromSources([]core.Source{{Location: location}}), location)
+	}
+}
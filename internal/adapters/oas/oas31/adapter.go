@@ -1,39 +1,138 @@
 package oas31
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"net/url"
+	"os"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+	"github.com/xxlv/ai-create-mcp/internal/overlay"
 )
 
 type OAS31Adapter struct {
-	oasPath string
+	oasPath  string
+	overlays []string
+	digest   string
+	applied  []*overlay.Overlay
 }
 
-func New(oasPath string) *OAS31Adapter {
-	return &OAS31Adapter{
+// Option configures an OAS31Adapter.
+type Option func(*OAS31Adapter)
+
+// WithOverlays applies the overlay documents at paths, in order, to the
+// spec after it is loaded.
+func WithOverlays(paths ...string) Option {
+	return func(a *OAS31Adapter) {
+		a.overlays = append(a.overlays, paths...)
+	}
+}
+
+func New(oasPath string, opts ...Option) *OAS31Adapter {
+	a := &OAS31Adapter{
 		oasPath: oasPath,
 	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
 }
-func (a *OAS31Adapter) ToTemplateData() (*core.TemplateData, error) {
 
-	var doc *openapi3.T
-	var err error
+// Load reads and resolves the OpenAPI document and applies the overlays,
+// without converting it.
+func (a *OAS31Adapter) Load() (*openapi3.T, error) {
+	doc, err := a.load()
+	if err != nil || len(a.overlays) == 0 {
+		return doc, err
+	}
+	return a.applyOverlays(doc)
+}
+
+func (a *OAS31Adapter) load() (*openapi3.T, error) {
+	a.digest = ""
+	loader := openapi3.NewLoader()
+	loader.ReadFromURIFunc = func(l *openapi3.Loader, location *url.URL) ([]byte, error) {
+		data, err := openapi3.DefaultReadFromURI(l, location)
+		if err == nil && a.digest == "" {
+			// the root document is always read first
+			sum := sha256.Sum256(data)
+			a.digest = hex.EncodeToString(sum[:])
+		}
+		return data, err
+	}
+
 	if strings.HasPrefix(a.oasPath, "http") || strings.HasPrefix(a.oasPath, "https") {
-		uri, parseErr := url.Parse(a.oasPath)
-		if parseErr != nil {
-			return nil, parseErr
+		uri, err := url.Parse(a.oasPath)
+		if err != nil {
+			return nil, err
 		}
-		doc, err = openapi3.NewLoader().LoadFromURI(uri)
-	} else {
-		doc, err = openapi3.NewLoader().LoadFromFile(a.oasPath)
+		return loader.LoadFromURI(uri)
 	}
+	return loader.LoadFromFile(a.oasPath)
+}
+
+// applyOverlays patches the JSON form of doc with every overlay and loads
+// the result again so references are resolved against the patched tree.
+func (a *OAS31Adapter) applyOverlays(doc *openapi3.T) (*openapi3.T, error) {
+	a.applied = nil
+	raw, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode spec: %v", err)
+	}
+	var tree any
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return nil, fmt.Errorf("failed to encode spec: %v", err)
+	}
+	for _, path := range a.overlays {
+		o, err := overlay.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		var unmatched []overlay.Action
+		if tree, unmatched, err = o.Apply(tree); err != nil {
+			return nil, fmt.Errorf("failed to apply overlay %s: %v", path, err)
+		}
+		for _, action := range unmatched {
+			fmt.Fprintf(os.Stderr, "WARN: overlay %s: target %s matches nothing\n", path, action.Target)
+		}
+		a.applied = append(a.applied, o)
+	}
+	if raw, err = json.Marshal(tree); err != nil {
+		return nil, fmt.Errorf("failed to encode spec: %v", err)
+	}
+	patched, err := openapi3.NewLoader().LoadFromData(raw)
+	if err != nil {
+		return nil, fmt.Errorf("spec is invalid after applying overlays: %v", err)
+	}
+	return patched, nil
+}
+
+func (a *OAS31Adapter) ToTemplateData() (*core.TemplateData, error) {
+	doc, err := a.Load()
 	if err != nil {
 		return nil, err
 	}
-	return convert(doc)
+	data, err := convert(doc)
+	if err != nil {
+		return nil, err
+	}
+	data.Sources = append(data.Sources, core.Source{
+		Type:     a.GetSourceType(),
+		Location: a.oasPath,
+		Digest:   a.digest,
+	})
+	for _, o := range a.applied {
+		data.Sources = append(data.Sources, core.Source{
+			Type:     "overlay",
+			Location: o.Location,
+			Digest:   o.Digest,
+		})
+	}
+	return data, nil
 }
 
 func (a *OAS31Adapter) GetSourceType() string {
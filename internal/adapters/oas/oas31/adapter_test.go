@@ -0,0 +1,63 @@
+package oas31
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petstore = "../../../../testdata/openapi.yml"
+
+func TestWithOverlays(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.yaml")
+	second := filepath.Join(dir, "second.yaml")
+	require.NoError(t, os.WriteFile(first, []byte(`
+overlay: 1.0.0
+info: {title: rename, version: 1.0.0}
+actions:
+  - target: $.paths['/pet/findByStatus'].get
+    update:
+      x-mcp-name: find_pets
+      summary: Find pets by status
+`), 0o644))
+	require.NoError(t, os.WriteFile(second, []byte(`
+overlay: 1.0.0
+info: {title: trim, version: 1.0.0}
+actions:
+  - target: $.paths[?@.delete].delete
+    remove: true
+  - target: $.paths['/pet/findByStatus'].get
+    update:
+      summary: Pets with a given status
+`), 0o644))
+
+	plain, err := New(petstore).ToTemplateData()
+	require.NoError(t, err)
+	data, err := New(petstore, WithOverlays(first, second)).ToTemplateData()
+	require.NoError(t, err)
+
+	tools := make(map[string]string)
+	for _, tool := range data.Tools {
+		assert.NotEqual(t, "DELETE", tool.Method, tool.Name)
+		tools[tool.Name] = tool.Description
+	}
+	assert.Equal(t, "Pets with a given status", tools["find_pets"])
+	assert.NotContains(t, tools, "get_pet_findByStatus")
+	assert.Less(t, len(data.Tools), len(plain.Tools))
+
+	require.Len(t, data.Sources, 3)
+	assert.Equal(t, plain.Sources[0], data.Sources[0])
+	assert.Equal(t, "overlay", data.Sources[1].Type)
+	assert.Equal(t, first, data.Sources[1].Location)
+	assert.Len(t, data.Sources[1].Digest, 64)
+	assert.Equal(t, second, data.Sources[2].Location)
+}
+
+func TestWithOverlaysErrors(t *testing.T) {
+	_, err := New(petstore, WithOverlays("missing.yaml")).ToTemplateData()
+	assert.ErrorContains(t, err, "failed to read overlay")
+}
@@ -0,0 +1,89 @@
+package shared
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+// deriveAnnotations maps HTTP semantics onto MCP tool annotations: safe
+// methods are read-only, DELETE is destructive and PUT/DELETE are idempotent.
+// Every tool reaches an external API, so all of them are open-world.
+func deriveAnnotations(method string, operation *openapi3.Operation) core.ToolAnnotations {
+	annotations := core.ToolAnnotations{
+		Title:         safeDesc(strings.TrimSpace(operation.Summary)),
+		OpenWorldHint: boolPtr(true),
+	}
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "OPTIONS":
+		annotations.ReadOnlyHint = boolPtr(true)
+		annotations.IdempotentHint = boolPtr(true)
+	case "DELETE":
+		annotations.ReadOnlyHint = boolPtr(false)
+		annotations.DestructiveHint = boolPtr(true)
+		annotations.IdempotentHint = boolPtr(true)
+	case "PUT":
+		annotations.ReadOnlyHint = boolPtr(false)
+		annotations.DestructiveHint = boolPtr(false)
+		annotations.IdempotentHint = boolPtr(true)
+	default:
+		annotations.ReadOnlyHint = boolPtr(false)
+		annotations.DestructiveHint = boolPtr(false)
+		annotations.IdempotentHint = boolPtr(false)
+	}
+	return annotations
+}
+
+// applyAnnotationsExtension overlays the x-mcp-annotations object of an
+// operation, if any, onto annotations.
+func applyAnnotationsExtension(annotations *core.ToolAnnotations, extensions map[string]any) error {
+	raw, ok := extensions[extAnnotations]
+	if !ok {
+		return nil
+	}
+	values, ok := raw.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s must be an object", extAnnotations)
+	}
+	for key, value := range values {
+		if key == "title" {
+			title, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("%s.title must be a string", extAnnotations)
+			}
+			annotations.Title = safeDesc(title)
+			continue
+		}
+		hint := annotationHint(annotations, key)
+		if hint == nil {
+			return fmt.Errorf("unknown key %s.%s", extAnnotations, key)
+		}
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("%s.%s must be a boolean", extAnnotations, key)
+		}
+		*hint = boolPtr(b)
+	}
+	return nil
+}
+
+// annotationHint returns the field of annotations named by its MCP key.
+func annotationHint(annotations *core.ToolAnnotations, key string) **bool {
+	switch key {
+	case "readOnlyHint":
+		return &annotations.ReadOnlyHint
+	case "destructiveHint":
+		return &annotations.DestructiveHint
+	case "idempotentHint":
+		return &annotations.IdempotentHint
+	case "openWorldHint":
+		return &annotations.OpenWorldHint
+	}
+	return nil
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
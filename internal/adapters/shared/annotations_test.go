@@ -0,0 +1,113 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+func TestDeriveAnnotations(t *testing.T) {
+	tests := []struct {
+		method      string
+		readOnly    bool
+		destructive *bool
+		idempotent  bool
+	}{
+		{method: "GET", readOnly: true, idempotent: true},
+		{method: "HEAD", readOnly: true, idempotent: true},
+		{method: "DELETE", destructive: boolPtr(true), idempotent: true},
+		{method: "PUT", destructive: boolPtr(false), idempotent: true},
+		{method: "POST", destructive: boolPtr(false)},
+		{method: "PATCH", destructive: boolPtr(false)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			got := deriveAnnotations(tt.method, &openapi3.Operation{Summary: `Do "it"`})
+			assert.Equal(t, "Do it", got.Title)
+			assert.Equal(t, tt.readOnly, *got.ReadOnlyHint)
+			assert.Equal(t, tt.destructive, got.DestructiveHint)
+			assert.Equal(t, tt.idempotent, *got.IdempotentHint)
+			assert.True(t, *got.OpenWorldHint)
+		})
+	}
+}
+
+func TestApplyAnnotationsExtension(t *testing.T) {
+	annotations := deriveAnnotations("DELETE", &openapi3.Operation{})
+	err := applyAnnotationsExtension(&annotations, map[string]any{
+		extAnnotations: map[string]any{
+			"title":           "Archive order",
+			"destructiveHint": false,
+			"openWorldHint":   false,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, core.ToolAnnotations{
+		Title:           "Archive order",
+		ReadOnlyHint:    boolPtr(false),
+		DestructiveHint: boolPtr(false),
+		IdempotentHint:  boolPtr(true),
+		OpenWorldHint:   boolPtr(false),
+	}, annotations)
+
+	for _, ext := range []any{
+		"yes",
+		map[string]any{"destructive": true},
+		map[string]any{"readOnlyHint": "true"},
+		map[string]any{"title": 1},
+	} {
+		err := applyAnnotationsExtension(&annotations, map[string]any{extAnnotations: ext})
+		assert.Error(t, err, "%v", ext)
+	}
+}
+
+func TestConvertConfirm(t *testing.T) {
+	data, err := convertYAML(t, `
+openapi: 3.0.3
+info: {title: Shop, version: 1.0.0}
+paths:
+  /orders/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    delete: {}
+    get: {}
+  /payments:
+    post:
+      x-mcp-confirm: true
+  /carts/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    delete:
+      x-mcp-confirm: false
+  /sessions/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    delete:
+      x-mcp-annotations: {destructiveHint: false}
+`)
+	require.NoError(t, err)
+	confirm := make(map[string]bool)
+	for _, tool := range data.Tools {
+		confirm[tool.Method+" "+tool.Path] = tool.NeedsConfirmation()
+	}
+	assert.Equal(t, map[string]bool{
+		"DELETE /orders/{id}":   true,
+		"GET /orders/{id}":      false,
+		"POST /payments":        true,
+		"DELETE /carts/{id}":    false,
+		"DELETE /sessions/{id}": false,
+	}, confirm)
+
+	_, err = convertYAML(t, `
+openapi: 3.0.3
+info: {title: Shop, version: 1.0.0}
+paths:
+  /payments:
+    post:
+      x-mcp-confirm: "yes"
+`)
+	assert.ErrorContains(t, err, "x-mcp-confirm must be a boolean")
+}
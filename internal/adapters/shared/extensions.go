@@ -0,0 +1,131 @@
+package shared
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Vendor extensions that let spec owners curate the MCP surface. See
+// docs/extensions.md for the full vocabulary.
+const (
+	extPrefix      = "x-mcp-"
+	extName        = "x-mcp-name"
+	extDescription = "x-mcp-description"
+	extExclude     = "x-mcp-exclude"
+	extResource    = "x-mcp-resource"
+	extPrompt      = "x-mcp-prompt"
+	extAnnotations = "x-mcp-annotations"
+	extRateLimit   = "x-mcp-rate-limit"
+	extConfirm     = "x-mcp-confirm"
+)
+
+// The extensions understood at each level of the document.
+var (
+	operationExtensions = []string{extName, extDescription, extExclude, extResource, extPrompt, extAnnotations, extRateLimit, extConfirm}
+	parameterExtensions = []string{extName, extDescription, extExclude}
+	schemaExtensions    = []string{extName, extDescription, extExclude}
+)
+
+// checkExtensions rejects x-mcp-* keys that are not in allowed, so a typo
+// does not silently change nothing.
+func checkExtensions(extensions map[string]any, allowed []string) error {
+	var unknown []string
+	for key := range extensions {
+		if !strings.HasPrefix(key, extPrefix) {
+			continue
+		}
+		if !contains(allowed, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("unknown extension %s, expected one of %s", strings.Join(unknown, ", "), strings.Join(allowed, ", "))
+}
+
+// extString returns the string value of key, or "" when it is absent.
+func extString(extensions map[string]any, key string) (string, error) {
+	raw, ok := extensions[key]
+	if !ok {
+		return "", nil
+	}
+	s, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", key)
+	}
+	return strings.TrimSpace(s), nil
+}
+
+// extBool returns the boolean value of key, or false when it is absent.
+func extBool(extensions map[string]any, key string) (bool, error) {
+	raw, ok := extensions[key]
+	if !ok {
+		return false, nil
+	}
+	b, ok := raw.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s must be a boolean", key)
+	}
+	return b, nil
+}
+
+// exposure is the parsed form of extensions such as x-mcp-resource and
+// x-mcp-prompt, which accept either a boolean or an object of overrides.
+type exposure struct {
+	Set     bool // the extension is present
+	Enabled bool
+	Fields  map[string]string
+}
+
+// extExposure parses key as a boolean or as an object whose string fields
+// are limited to allowed. An object implies Enabled.
+func extExposure(extensions map[string]any, key string, allowed ...string) (exposure, error) {
+	raw, ok := extensions[key]
+	if !ok {
+		return exposure{}, nil
+	}
+	switch v := raw.(type) {
+	case bool:
+		return exposure{Set: true, Enabled: v}, nil
+	case map[string]any:
+		e := exposure{Set: true, Enabled: true, Fields: make(map[string]string, len(v))}
+		for field, value := range v {
+			if !contains(allowed, field) {
+				return exposure{}, fmt.Errorf("unknown key %s.%s, expected one of %s", key, field, strings.Join(allowed, ", "))
+			}
+			s, ok := value.(string)
+			if !ok {
+				return exposure{}, fmt.Errorf("%s.%s must be a string", key, field)
+			}
+			e.Fields[field] = s
+		}
+		return e, nil
+	}
+	return exposure{}, fmt.Errorf("%s must be a boolean or an object", key)
+}
+
+// naming holds the x-mcp-name, x-mcp-description and x-mcp-exclude values
+// shared by operations, parameters and schemas.
+type naming struct {
+	Name        string
+	Description string
+	Exclude     bool
+}
+
+func extNaming(extensions map[string]any) (naming, error) {
+	var n naming
+	var err error
+	if n.Name, err = extString(extensions, extName); err != nil {
+		return n, err
+	}
+	if n.Description, err = extString(extensions, extDescription); err != nil {
+		return n, err
+	}
+	if n.Exclude, err = extBool(extensions, extExclude); err != nil {
+		return n, err
+	}
+	return n, nil
+}
@@ -0,0 +1,239 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+func convertYAML(t *testing.T, spec string) (*core.TemplateData, error) {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	return Convert(doc)
+}
+
+const extensionsSpec = `
+openapi: 3.0.3
+info:
+  title: Orders
+  version: 1.0.0
+servers:
+  - url: https://orders.test
+paths:
+  /orders/{id}:
+    get:
+      summary: Fetch an order
+      x-mcp-name: fetch_order
+      x-mcp-description: Fetch one order by its id
+      x-mcp-resource:
+        name: order
+        mimeType: application/json
+      x-mcp-prompt: false
+      parameters:
+        - name: id
+          in: path
+          required: true
+          x-mcp-name: order_id
+          schema:
+            type: string
+        - name: X-Debug
+          in: header
+          x-mcp-exclude: true
+          schema:
+            type: boolean
+    put:
+      summary: Replace an order
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [id]
+              properties:
+                id:
+                  type: string
+                note:
+                  type: string
+                  x-mcp-description: Free text shown to the warehouse
+                internal_code:
+                  type: string
+                  x-mcp-exclude: true
+    delete:
+      x-mcp-exclude: true
+`
+
+func TestConvertExtensions(t *testing.T) {
+	data, err := convertYAML(t, extensionsSpec)
+	require.NoError(t, err)
+
+	require.Len(t, data.Tools, 2)
+	fetch := data.Tools[0]
+	assert.Equal(t, "fetch_order", fetch.Name)
+	assert.Equal(t, "Fetch one order by its id", fetch.Description)
+	assert.Equal(t, []core.Argument{
+		{Name: "order_id", Required: true, Type: "string", In: "path", WireName: "id", Schema: map[string]any{"type": "string"}},
+	}, fetch.Arguments)
+
+	replace := data.Tools[1]
+	assert.Equal(t, "put_orders_by_id", replace.Name)
+	assert.Equal(t, []core.Argument{
+		{Name: "id", Required: true, Type: "string", In: "path", WireName: "id", Schema: map[string]any{"type": "string"}},
+		{Name: "body_id", Required: true, Type: "string", In: "body", WireName: "id", Schema: map[string]any{"type": "string"}},
+		{Name: "note", Description: "Free text shown to the warehouse", Type: "string", In: "body", WireName: "note", Schema: map[string]any{"type": "string"}},
+	}, replace.Arguments)
+
+	require.Len(t, data.Resources, 1)
+	assert.Equal(t, "order", data.Resources[0].Name)
+	assert.Equal(t, "application/json", data.Resources[0].MimeType)
+	assert.Equal(t, "Fetch one order by its id", data.Resources[0].Description)
+	// the path parameter is a variable of the resource template, named as in the path
+	assert.True(t, data.Resources[0].IsTemplate())
+	assert.Equal(t, fetch.Arguments, data.Resources[0].Arguments)
+	assert.Contains(t, data.Resources[0].URI, "{id}")
+	assert.Empty(t, data.Prompts)
+}
+
+func TestConvertExtensionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		op   string
+		want string
+	}{
+		{
+			name: "unknown operation extension",
+			op:   "x-mcp-nmae: typo",
+			want: "unknown extension x-mcp-nmae",
+		},
+		{
+			name: "wrong type",
+			op:   "x-mcp-exclude: \"yes\"",
+			want: "x-mcp-exclude must be a boolean",
+		},
+		{
+			name: "unknown resource key",
+			op:   "x-mcp-resource: {uri: x}",
+			want: "unknown key x-mcp-resource.uri",
+		},
+		{
+			name: "unknown parameter extension",
+			op: `parameters:
+        - name: q
+          in: query
+          x-mcp-hidden: true
+          schema:
+            type: string`,
+			want: "parameter q: unknown extension x-mcp-hidden",
+		},
+		{
+			name: "excluded required parameter",
+			op: `parameters:
+        - name: q
+          in: query
+          required: true
+          x-mcp-exclude: true
+          schema:
+            type: string`,
+			want: "required parameters cannot use x-mcp-exclude",
+		},
+		{
+			name: "unknown prompt placeholder",
+			op:   "x-mcp-prompt: {template: 'Search for {query}'}",
+			want: "template uses {query}, which is not one of its arguments",
+		},
+		{
+			name: "renamed into a collision",
+			op: `parameters:
+        - name: q
+          in: query
+          schema:
+            type: string
+        - name: query
+          in: query
+          x-mcp-name: q
+          schema:
+            type: string`,
+			want: `duplicate argument name "q"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := `
+openapi: 3.0.3
+info:
+  title: Search
+  version: 1.0.0
+paths:
+  /search:
+    get:
+      summary: Search
+      ` + tt.op + `
+`
+			_, err := convertYAML(t, spec)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "GET /search")
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestExtensionsOnlyOnGet(t *testing.T) {
+	_, err := convertYAML(t, `
+openapi: 3.0.3
+info:
+  title: Orders
+  version: 1.0.0
+paths:
+  /orders:
+    post:
+      summary: Create
+      x-mcp-resource: true
+`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only supported on GET operations")
+}
+
+func TestConvertPrompts(t *testing.T) {
+	data, err := convertYAML(t, `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets/{petId}:
+    get:
+      summary: Find pet by ID
+      x-mcp-prompt:
+        name: summarize_pet
+        template: Look up pet {petId} with {tool} and summarize its status.
+        description: Summarize a "pet"
+      parameters:
+        - {name: petId, in: path, required: true, schema: {type: integer}}
+  /pets:
+    get:
+      summary: List pets
+`)
+	require.NoError(t, err)
+	require.Len(t, data.Prompts, 2)
+
+	list, summarize := data.Prompts[0], data.Prompts[1]
+	assert.Equal(t, "get_pets", list.Name)
+	assert.Equal(t, "get_pets", list.Tool)
+	assert.Empty(t, list.Template)
+	assert.True(t, list.Auto)
+
+	assert.Equal(t, "summarize_pet", summarize.Name)
+	assert.Equal(t, "get_pets_by_petId", summarize.Tool)
+	assert.Equal(t, "Look up pet {petId} with {tool} and summarize its status.", summarize.Template)
+	assert.Equal(t, "Summarize a pet", summarize.Description)
+	assert.False(t, summarize.Auto)
+}
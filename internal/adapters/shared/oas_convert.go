@@ -2,7 +2,9 @@ package shared
 
 import (
 	"fmt"
+	"os"
 	"slices"
+	"sort"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -44,89 +46,392 @@ func Convert(doc *openapi3.T) (*core.TemplateData, error) {
 		ServerName:    doc.Info.Title,
 		ServerVersion: doc.Info.Version,
 		Endpoints:     endpoints, // multiple endpoints
+		Upstreams: []core.Upstream{{
+			Name:        core.DefaultUpstream,
+			Endpoints:   endpoints,
+			MissBaseURL: missBaseUrl,
+			Auth:        core.Auth{Type: core.AuthBearer, Name: "Authorization", Env: "TOKEN"},
+		}},
 	}
+	rateLimit, err := extRateLimitValue(doc.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	data.Upstreams[0].RateLimit = rateLimit
 	if len(doc.Servers) > 1 {
-		fmt.Printf("WARN: mutlple servers found in oas config file,current just pick the frist!\n")
+		fmt.Fprintf(os.Stderr, "WARN: mutlple servers found in oas config file,current just pick the frist!\n")
 	}
-	for path, pathItem := range doc.Paths.Map() {
+	// walk paths and methods in a stable order so the generated files are reproducible
+	pathItems := doc.Paths.Map()
+	for _, path := range sortedKeys(pathItems) {
+		pathItem := pathItems[path]
 		cleanPath := strings.TrimPrefix(path, "/")
 		cleanPath = strings.ReplaceAll(cleanPath, "/", "_")
 
-		for method, operation := range pathItem.Operations() {
-			opName := generateToolName(method, cleanPath)
-			description := operation.Summary
-			if description == "" {
-				description = operation.Description
-			}
-			if description == "" {
-				description = fmt.Sprintf("%s operation on %s", method, path)
+		operations := pathItem.Operations()
+		for _, method := range sortedKeys(operations) {
+			if err := convertOperation(data, method, path, cleanPath, operations[method]); err != nil {
+				return nil, fmt.Errorf("%s %s: %v", method, path, err)
 			}
+		}
+	}
+	return data, nil
+}
 
-			var arguments []core.Argument
-			for _, param := range operation.Parameters {
-				arg := core.Argument{
-					Name:        safe(param.Value.Name),
-					Description: safeDesc(param.Value.Description),
-					Required:    param.Value.Required,
-				}
-				arguments = append(arguments, arg)
-			}
+// convertOperation adds the tool, resource and prompt generated from one
+// operation to data, honoring its x-mcp-* extensions.
+func convertOperation(data *core.TemplateData, method, path, cleanPath string, operation *openapi3.Operation) error {
+	if err := checkExtensions(operation.Extensions, operationExtensions); err != nil {
+		return err
+	}
+	opNaming, err := extNaming(operation.Extensions)
+	if err != nil {
+		return err
+	}
+	if opNaming.Exclude {
+		return nil
+	}
+	resourceExt, err := extExposure(operation.Extensions, extResource, "name", "description", "mimeType")
+	if err != nil {
+		return err
+	}
+	promptExt, err := extExposure(operation.Extensions, extPrompt, "name", "description", "template")
+	if err != nil {
+		return err
+	}
 
-			if method == "GET" {
-				mimeType := "text/plain"
-				if operation.Responses != nil {
-					if resp, ok := operation.Responses.Map()["200"]; ok && resp.Value != nil {
-						for contentType := range resp.Value.Content {
-							mimeType = contentType
-							break
-						}
-					}
-				}
-				resource := core.Resource{
-					Name:        safe(fmt.Sprintf("Resource: %s", cleanPath)),
-					Description: safeDesc(description),
-					URI:         fmt.Sprintf("ai-create-mcp://internal/%s", cleanPath),
-					MimeType:    mimeType,
-				}
-				data.Resources = append(data.Resources, resource)
+	opName := generateToolName(method, cleanPath)
+	if opNaming.Name != "" {
+		opName = opNaming.Name
+	}
+	description := operation.Summary
+	if description == "" {
+		description = operation.Description
+	}
+	if description == "" {
+		description = fmt.Sprintf("%s operation on %s", method, path)
+	}
+	if opNaming.Description != "" {
+		description = opNaming.Description
+	}
 
-				prompt := core.Prompt{
-					Name:        safe(opName),
-					Description: description,
-					Arguments:   arguments,
-				}
-				data.Prompts = append(data.Prompts, prompt)
-			}
-
-			if method == "GET" || method == "POST" || method == "PUT" || method == "PATCH" || method == "DELETE" {
-				if operation.RequestBody != nil && operation.RequestBody.Value != nil {
-					for _, content := range operation.RequestBody.Value.Content {
-						if content.Schema != nil && content.Schema.Value != nil {
-							for propName, prop := range content.Schema.Value.Properties {
-								arg := core.Argument{
-									Name:        safe(propName),
-									Description: safeDesc(prop.Value.Description),
-									Required:    contains(prop.Value.Required, propName),
-								}
-								arguments = append(arguments, arg)
-							}
-						}
+	// one resolver per tool, so recursive schemas end up in its $defs
+	r := newResolver()
+	arguments, err := parameterArguments(r, operation.Parameters)
+	if err != nil {
+		return err
+	}
+
+	if method == "GET" {
+		if !resourceExt.Set || resourceExt.Enabled {
+			mimeType := "text/plain"
+			if operation.Responses != nil {
+				if resp, ok := operation.Responses.Map()["200"]; ok && resp.Value != nil {
+					for _, contentType := range sortedKeys(resp.Value.Content) {
+						mimeType = contentType
 						break
 					}
 				}
+			}
+			resource := core.Resource{
+				Name:        safe(fmt.Sprintf("Resource: %s", cleanPath)),
+				Description: safeDesc(description),
+				URI:         fmt.Sprintf("ai-create-mcp://internal/%s", cleanPath),
+				MimeType:    mimeType,
+			}
+			if name := resourceExt.Fields["name"]; name != "" {
+				resource.Name = safe(name)
+			}
+			if desc := resourceExt.Fields["description"]; desc != "" {
+				resource.Description = safeDesc(desc)
+			}
+			if mime := resourceExt.Fields["mimeType"]; mime != "" {
+				resource.MimeType = mime
+			}
+			for _, arg := range arguments {
+				if arg.In == openapi3.ParameterInPath {
+					resource.Arguments = append(resource.Arguments, arg)
+				}
+			}
+			data.Resources = append(data.Resources, resource)
+		}
+
+		if !promptExt.Set || promptExt.Enabled {
+			prompt := core.Prompt{
+				Name:        safe(opName),
+				Description: safeDesc(description),
+				Arguments:   arguments,
+				Tool:        safe(opName),
+				Template:    promptExt.Fields["template"],
+				Auto:        !promptExt.Set,
+			}
+			if name := promptExt.Fields["name"]; name != "" {
+				prompt.Name = safe(name)
+			}
+			if desc := promptExt.Fields["description"]; desc != "" {
+				prompt.Description = safeDesc(desc)
+			}
+			if err := prompt.CheckTemplate(); err != nil {
+				return err
+			}
+			data.Prompts = append(data.Prompts, prompt)
+		}
+	} else if resourceExt.Enabled || promptExt.Enabled {
+		return fmt.Errorf("%s and %s are only supported on GET operations", extResource, extPrompt)
+	}
 
-				tool := core.Tool{
-					Name:        safe(opName),
-					Description: safeDesc(description),
-					Arguments:   arguments,
-					Method:      method,
-					Path:        path,
+	if method == "GET" || method == "POST" || method == "PUT" || method == "PATCH" || method == "DELETE" {
+		var requestType string
+		var requestTypes []string
+		if operation.RequestBody != nil && operation.RequestBody.Value != nil {
+			body := operation.RequestBody.Value
+			requestType = RequestContentType(body.Content)
+			requestTypes = sortedKeys(body.Content)
+			if requestType != "" {
+				bodyArgs, err := bodyArguments(r, body.Content[requestType].Schema, requestType, body.Required)
+				if err != nil {
+					return err
 				}
-				data.Tools = append(data.Tools, tool)
+				arguments = append(arguments, disambiguateBody(arguments, bodyArgs)...)
 			}
 		}
+		if err := checkArgumentNames(arguments); err != nil {
+			return err
+		}
+
+		annotations := deriveAnnotations(method, operation)
+		if err := applyAnnotationsExtension(&annotations, operation.Extensions); err != nil {
+			return err
+		}
+		rateLimit, err := extRateLimitValue(operation.Extensions)
+		if err != nil {
+			return err
+		}
+		var confirm *bool
+		if _, ok := operation.Extensions[extConfirm]; ok {
+			value, err := extBool(operation.Extensions, extConfirm)
+			if err != nil {
+				return err
+			}
+			confirm = &value
+		}
+		output, wrap := outputSchema(operation.Responses)
+		tool := core.Tool{
+			Name:          safe(opName),
+			Description:   safeDesc(description),
+			Arguments:     arguments,
+			Method:        method,
+			Path:          path,
+			Annotations:   annotations,
+			Upstream:      core.DefaultUpstream,
+			Tags:          operation.Tags,
+			InputSchema:   inputSchema(arguments, r.defs),
+			OutputSchema:  output,
+			WrapOutput:    wrap,
+			ResponseTypes: responseTypes(operation.Responses),
+			RequestType:   requestType,
+			RequestTypes:  requestTypes,
+			RateLimit:     rateLimit,
+			Pagination:    detectPagination(arguments),
+			Confirm:       confirm,
+		}
+		data.Tools = append(data.Tools, tool)
 	}
-	return data, nil
+	return nil
+}
+
+// parameterArguments converts operation parameters into tool arguments.
+func parameterArguments(r *resolver, params openapi3.Parameters) ([]core.Argument, error) {
+	var arguments []core.Argument
+	for _, param := range params {
+		if param.Value == nil {
+			continue
+		}
+		p := param.Value
+		if err := checkExtensions(p.Extensions, parameterExtensions); err != nil {
+			return nil, fmt.Errorf("parameter %s: %v", p.Name, err)
+		}
+		n, err := extNaming(p.Extensions)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %v", p.Name, err)
+		}
+		if n.Exclude {
+			if p.Required {
+				return nil, fmt.Errorf("parameter %s: required parameters cannot use %s", p.Name, extExclude)
+			}
+			continue
+		}
+		arg := core.Argument{
+			Name:        safe(p.Name),
+			Description: safeDesc(p.Description),
+			Required:    p.Required,
+			Type:        schemaType(p.Schema),
+			In:          p.In,
+			WireName:    p.Name,
+			Schema:      r.schema(p.Schema),
+		}
+		examples := []any{p.Example}
+		for _, name := range sortedKeys(p.Examples) {
+			if example := p.Examples[name]; example != nil && example.Value != nil {
+				examples = append(examples, example.Value.Value)
+			}
+		}
+		arg.Enum, arg.Examples = argumentValues(p.Schema, examples...)
+		applyNaming(&arg, n)
+		arguments = append(arguments, arg)
+	}
+	return arguments, nil
+}
+
+// bodyArguments converts the top-level properties of a request body schema,
+// including those inherited through allOf, into tool arguments. Any other
+// body, such as an array, a oneOf union or a file upload, is taken as a
+// single "body" argument holding the whole payload.
+func bodyArguments(r *resolver, ref *openapi3.SchemaRef, contentType string, required bool) ([]core.Argument, error) {
+	schema := flatSchema(ref)
+	if !isObjectSchema(schema) || len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 {
+		arg := core.Argument{
+			Name:        "body",
+			Description: "Request body",
+			Required:    required,
+			Type:        schemaType(ref),
+			In:          core.InRawBody,
+			Binary:      isBinary(ref) || (ref == nil && !isText(contentType)),
+			Schema:      r.schema(ref),
+		}
+		if schema != nil && schema.Description != "" {
+			arg.Description = safeDesc(schema.Description)
+		}
+		describeBinary(&arg)
+		return []core.Argument{arg}, nil
+	}
+	var arguments []core.Argument
+	var err error
+	r.within(ref, func() {
+		arguments, err = propertyArguments(r, schema)
+	})
+	return arguments, err
+}
+
+// propertyArguments converts the properties of an object body.
+func propertyArguments(r *resolver, schema *openapi3.Schema) ([]core.Argument, error) {
+	var arguments []core.Argument
+	for _, propName := range sortedKeys(schema.Properties) {
+		prop := schema.Properties[propName]
+		if prop.Value == nil {
+			continue
+		}
+		if err := checkExtensions(prop.Value.Extensions, schemaExtensions); err != nil {
+			return nil, fmt.Errorf("property %s: %v", propName, err)
+		}
+		n, err := extNaming(prop.Value.Extensions)
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %v", propName, err)
+		}
+		if n.Exclude {
+			if contains(schema.Required, propName) {
+				return nil, fmt.Errorf("property %s: required properties cannot use %s", propName, extExclude)
+			}
+			continue
+		}
+		required := contains(schema.Required, propName)
+		if prop.Value.ReadOnly && !required {
+			// set by the server, never sent
+			continue
+		}
+		arg := core.Argument{
+			Name:        safe(propName),
+			Description: safeDesc(prop.Value.Description),
+			Required:    required,
+			Type:        schemaType(prop),
+			In:          core.InBody,
+			WireName:    propName,
+			Binary:      isBinary(prop),
+			Schema:      r.schema(prop),
+		}
+		arg.Enum, arg.Examples = argumentValues(prop)
+		applyNaming(&arg, n)
+		describeBinary(&arg)
+		arguments = append(arguments, arg)
+	}
+	return arguments, nil
+}
+
+// describeBinary tells the model how to pass file content.
+func describeBinary(arg *core.Argument) {
+	if !arg.Binary {
+		return
+	}
+	// the model sends text, never raw bytes
+	arg.Schema = map[string]any{"type": "string"}
+	hint := "Base64 encoded content or the path of a local file."
+	if arg.Description == "" {
+		arg.Description = hint
+	} else {
+		arg.Description = strings.TrimRight(arg.Description, ". ") + ". " + hint
+	}
+}
+
+func applyNaming(arg *core.Argument, n naming) {
+	if n.Name != "" {
+		arg.Name = safe(n.Name)
+	}
+	if n.Description != "" {
+		arg.Description = safeDesc(n.Description)
+	}
+}
+
+// inputSchema builds the JSON Schema of the tool arguments. defs holds the
+// recursive schemas the arguments refer to.
+func inputSchema(arguments []core.Argument, defs map[string]any) map[string]any {
+	properties := make(map[string]any, len(arguments))
+	required := []string{}
+	for _, arg := range arguments {
+		schema := copyMap(arg.Schema)
+		if arg.Description != "" {
+			schema["description"] = arg.Description
+		}
+		properties[arg.Name] = schema
+		if arg.Required {
+			required = append(required, arg.Name)
+		}
+	}
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+	if len(defs) > 0 {
+		schema["$defs"] = defs
+	}
+	return schema
+}
+
+// disambiguateBody prefixes body arguments whose name is already taken by a
+// parameter, e.g. a "username" property next to a {username} path parameter.
+func disambiguateBody(params, body []core.Argument) []core.Argument {
+	taken := make(map[string]bool, len(params))
+	for _, arg := range params {
+		taken[arg.Name] = true
+	}
+	for i := range body {
+		if taken[body[i].Name] {
+			body[i].Name = "body_" + body[i].Name
+		}
+	}
+	return body
+}
+
+// checkArgumentNames reports arguments that collide after renaming.
+func checkArgumentNames(arguments []core.Argument) error {
+	seen := make(map[string]bool, len(arguments))
+	for _, arg := range arguments {
+		if seen[arg.Name] {
+			return fmt.Errorf("duplicate argument name %q, use %s to rename one of them", arg.Name, extName)
+		}
+		seen[arg.Name] = true
+	}
+	return nil
 }
 
 func safe(name string) string {
@@ -145,6 +450,46 @@ func safeDesc(name string) string {
 	return name
 }
 
+// argumentValues collects the enum and examples of an argument schema,
+// looking at the items of an array, after the examples given outside the
+// schema such as those of a parameter.
+func argumentValues(ref *openapi3.SchemaRef, examples ...any) (enum, found []any) {
+	schema := flatSchema(ref)
+	if schema != nil && schema.Type.Is(openapi3.TypeArray) && schema.Items != nil {
+		schema = flatSchema(schema.Items)
+	}
+	if schema != nil {
+		enum = schema.Enum
+		examples = append(examples, schema.Example)
+	}
+	for _, example := range examples {
+		if example != nil {
+			found = append(found, example)
+		}
+	}
+	return enum, found
+}
+
+// schemaType returns the first JSON Schema type declared by ref, or an empty
+// string when it declares none.
+func schemaType(ref *openapi3.SchemaRef) string {
+	if ref == nil || ref.Value == nil || ref.Value.Type == nil || len(*ref.Value.Type) == 0 {
+		return ""
+	}
+	return (*ref.Value.Type)[0]
+}
+
 func contains(slice []string, item string) bool {
 	return slices.Contains(slice, item)
 }
+
+// sortedKeys returns the keys of m in lexical order, keeping the output of
+// Convert independent of map iteration order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
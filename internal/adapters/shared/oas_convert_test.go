@@ -144,11 +144,31 @@ func TestConvertOAStoTemplateData(t *testing.T) {
 							{
 								Name:        "name",
 								Description: "User name",
-								Required:    false,
+								Required:    true,
+								In:          "body",
+								WireName:    "name",
+								Schema:      map[string]any{"description": "User name"},
 							},
 						},
 						Method: "POST",
 						Path:   "/users",
+						Annotations: core.ToolAnnotations{
+							Title:           "Create user",
+							ReadOnlyHint:    boolPtr(false),
+							DestructiveHint: boolPtr(false),
+							IdempotentHint:  boolPtr(false),
+							OpenWorldHint:   boolPtr(true),
+						},
+						Upstream: core.DefaultUpstream,
+						InputSchema: map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"name": map[string]any{"description": "User name"},
+							},
+							"required": []string{"name"},
+						},
+						RequestType:  "application/json",
+						RequestTypes: []string{"application/json"},
 					},
 				},
 			},
@@ -202,3 +222,146 @@ func TestConvertOAStoTemplateData(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertRequestBodies(t *testing.T) {
+	data, err := convertYAML(t, `
+openapi: 3.0.0
+info: {title: Test, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/xml:
+            schema: {type: object, properties: {name: {type: string}}}
+          application/x-www-form-urlencoded:
+            schema: {type: object, properties: {name: {type: string}}}
+          application/json:
+            schema: {type: object, properties: {name: {type: string}}}
+  /pets/{id}/photo:
+    put:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              properties:
+                caption: {type: string}
+                file: {type: string, format: binary, description: The photo.}
+  /pets/{id}/raw:
+    put:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      requestBody:
+        required: true
+        content:
+          application/octet-stream: {}
+  /pets/batch:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {type: array, items: {type: string}, description: Pet names}
+`)
+	require.NoError(t, err)
+	tools := make(map[string]core.Tool)
+	for _, tool := range data.Tools {
+		tools[tool.Name] = tool
+	}
+
+	post := tools["post_pets"]
+	assert.Equal(t, "application/json", post.RequestType)
+	assert.Equal(t, []string{"application/json", "application/x-www-form-urlencoded", "application/xml"}, post.RequestTypes)
+
+	photo := tools["put_pets_by_id_photo"]
+	assert.Equal(t, "multipart/form-data", photo.RequestType)
+	assert.Equal(t, []core.Argument{
+		{Name: "id", Required: true, Type: "string", In: "path", WireName: "id", Schema: map[string]any{"type": "string"}},
+		{Name: "caption", Type: "string", In: core.InBody, WireName: "caption", Schema: map[string]any{"type": "string"}},
+		{Name: "file", Description: "The photo. Base64 encoded content or the path of a local file.", Type: "string", In: core.InBody, WireName: "file", Binary: true, Schema: map[string]any{"type": "string"}},
+	}, photo.Arguments)
+
+	raw := tools["put_pets_by_id_raw"]
+	assert.Equal(t, "application/octet-stream", raw.RequestType)
+	assert.Equal(t, core.Argument{
+		Name: "body", Description: "Request body. Base64 encoded content or the path of a local file.", Required: true, In: core.InRawBody, Binary: true, Schema: map[string]any{"type": "string"},
+	}, raw.Arguments[1])
+
+	batch := tools["post_pets_batch"]
+	assert.Equal(t, []core.Argument{
+		{Name: "body", Description: "Pet names", Type: "array", In: core.InRawBody, Schema: map[string]any{
+			"type": "array", "items": map[string]any{"type": "string"}, "description": "Pet names",
+		}},
+	}, batch.Arguments)
+}
+
+func TestConvertArgumentValues(t *testing.T) {
+	data, err := convertYAML(t, `
+openapi: 3.0.0
+info: {title: Test, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: status
+          in: query
+          schema: {type: string, enum: [available, pending, sold], example: pending}
+        - name: tags
+          in: query
+          schema: {type: array, items: {type: string, enum: [cat, dog]}}
+        - name: limit
+          in: query
+          example: 20
+          examples:
+            few: {value: 5}
+          schema: {type: integer, example: 20}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name: {type: string, example: Rex}
+                collar: {type: object, example: {color: red}}
+`)
+	require.NoError(t, err)
+	tools := make(map[string]core.Tool)
+	for _, tool := range data.Tools {
+		tools[tool.Name] = tool
+	}
+
+	get := tools["get_pets"].Arguments
+	assert.Equal(t, []any{"available", "pending", "sold"}, get[0].Enum)
+	assert.Equal(t, []string{"available", "pending", "sold"}, get[0].Completions())
+	assert.Equal(t, []string{"cat", "dog"}, get[1].Completions())
+	assert.Equal(t, []any{20.0, 5.0, 20.0}, get[2].Examples)
+	assert.Equal(t, []string{"20", "5"}, get[2].Completions())
+
+	post := tools["post_pets"].Arguments
+	assert.Equal(t, []string{"Rex"}, post[1].Completions())
+	// objects cannot be typed in as completions
+	assert.Empty(t, post[0].Completions())
+}
+
+func TestRequestContentType(t *testing.T) {
+	tests := []struct {
+		types []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{"application/xml", "application/vnd.api+json"}, "application/vnd.api+json"},
+		{[]string{"multipart/form-data", "application/x-www-form-urlencoded"}, "application/x-www-form-urlencoded"},
+		{[]string{"text/plain", "multipart/mixed"}, "multipart/mixed"},
+		{[]string{"text/plain", "application/octet-stream"}, "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		content := openapi3.Content{}
+		for _, contentType := range tt.types {
+			content[contentType] = openapi3.NewMediaType()
+		}
+		assert.Equal(t, tt.want, RequestContentType(content), tt.types)
+	}
+}
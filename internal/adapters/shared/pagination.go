@@ -0,0 +1,63 @@
+package shared
+
+import (
+	"strings"
+
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+// Query parameter names of common paging schemes, lower case without
+// separators, most common first.
+var (
+	cursorParams = []string{"cursor", "pagetoken", "nexttoken", "continuationtoken", "startingafter", "after", "marker"}
+	pageParams   = []string{"page", "pagenumber", "pageno"}
+	offsetParams = []string{"offset", "skip"}
+	sizeParams   = []string{"limit", "perpage", "pagesize", "size", "count", "maxresults"}
+)
+
+// detectPagination recognizes the paging scheme of an operation from its
+// query arguments. Cursors win over page numbers, which win over offsets.
+// Responses paged with a Link header need no arguments recognized here.
+func detectPagination(arguments []core.Argument) *core.Pagination {
+	byName := make(map[string]core.Argument)
+	for _, arg := range arguments {
+		if arg.In != "query" {
+			continue
+		}
+		name := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(arg.WireName))
+		if _, ok := byName[name]; !ok {
+			byName[name] = arg
+		}
+	}
+	find := func(names []string, numeric bool) *core.Argument {
+		for _, name := range names {
+			arg, ok := byName[name]
+			if ok && (!numeric || arg.Type == "" || arg.Type == "integer" || arg.Type == "number") {
+				return &arg
+			}
+		}
+		return nil
+	}
+
+	var size string
+	if arg := find(sizeParams, true); arg != nil {
+		size = arg.Name
+	}
+	if arg := find(cursorParams, false); arg != nil {
+		return &core.Pagination{Style: core.PageByCursor, Argument: arg.Name, Size: size}
+	}
+	if arg := find(pageParams, true); arg != nil {
+		start := 1
+		switch def := arg.Schema["default"].(type) {
+		case float64:
+			start = int(def)
+		case int:
+			start = def
+		}
+		return &core.Pagination{Style: core.PageByNumber, Argument: arg.Name, Size: size, Start: start}
+	}
+	if arg := find(offsetParams, true); arg != nil {
+		return &core.Pagination{Style: core.PageByOffset, Argument: arg.Name, Size: size}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+func TestConvertPagination(t *testing.T) {
+	data, err := convertYAML(t, `
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      parameters:
+        - {name: page, in: query, schema: {type: integer, default: 0}}
+        - {name: per_page, in: query, schema: {type: integer}}
+  /owners:
+    get:
+      parameters:
+        - {name: offset, in: query, schema: {type: integer}}
+        - {name: page_token, in: query, schema: {type: string}}
+  /toys:
+    get:
+      parameters:
+        - {name: page, in: path, required: true, schema: {type: string}}
+`)
+	require.NoError(t, err)
+	pagination := make(map[string]*core.Pagination)
+	for _, tool := range data.Tools {
+		pagination[tool.Path] = tool.Pagination
+	}
+	assert.Equal(t, &core.Pagination{Style: core.PageByNumber, Argument: "page", Size: "per_page"}, pagination["/pets"])
+	assert.Equal(t, &core.Pagination{Style: core.PageByCursor, Argument: "page_token"}, pagination["/owners"])
+	assert.Nil(t, pagination["/toys"])
+}
+
+func TestDetectPagination(t *testing.T) {
+	query := func(name, typ string) core.Argument {
+		return core.Argument{Name: name, WireName: name, In: "query", Type: typ}
+	}
+	tests := []struct {
+		name      string
+		arguments []core.Argument
+		want      *core.Pagination
+	}{
+		{"none", []core.Argument{query("status", "string")}, nil},
+		{"page starting at 1", []core.Argument{query("page", "integer"), query("pageSize", "integer")}, &core.Pagination{Style: core.PageByNumber, Argument: "page", Size: "pageSize", Start: 1}},
+		{"page given as a word", []core.Argument{query("page", "string")}, nil},
+		{"offset", []core.Argument{query("limit", ""), query("skip", "integer")}, &core.Pagination{Style: core.PageByOffset, Argument: "skip", Size: "limit"}},
+		{"cursor over offset", []core.Argument{query("offset", "integer"), query("starting_after", "string")}, &core.Pagination{Style: core.PageByCursor, Argument: "starting_after"}},
+		{"renamed argument", []core.Argument{{Name: "q_cursor", WireName: "cursor", In: "query"}}, &core.Pagination{Style: core.PageByCursor, Argument: "q_cursor"}},
+		{"header", []core.Argument{{Name: "cursor", WireName: "cursor", In: "header"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectPagination(tt.arguments))
+		})
+	}
+}
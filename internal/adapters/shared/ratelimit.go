@@ -0,0 +1,99 @@
+package shared
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+// rateLimitFallbacks are the rate limit extensions of other tools, read in
+// this order when there is no x-mcp-rate-limit. Each holds a rate such as
+// 100/m, a number of requests per second, or an x-mcp-rate-limit object.
+var rateLimitFallbacks = []string{"x-ratelimit", "x-rate-limit", "x-ratelimit-limit"}
+
+// extRateLimitValue parses the x-mcp-rate-limit object of the document or of
+// an operation, if any:
+//
+//	x-mcp-rate-limit:
+//	  rate: 10/s
+//	  burst: 20
+//	  concurrency: 4
+//
+// Without it, the first of rateLimitFallbacks found is used. Those are not
+// ours to validate, so one in another shape is ignored with a warning.
+func extRateLimitValue(extensions map[string]any) (core.RateLimit, error) {
+	if raw, ok := extensions[extRateLimit]; ok {
+		values, ok := raw.(map[string]any)
+		if !ok {
+			return core.RateLimit{}, fmt.Errorf("%s must be an object", extRateLimit)
+		}
+		return rateLimitObject(extRateLimit, values)
+	}
+	for _, name := range rateLimitFallbacks {
+		raw, ok := extensions[name]
+		if !ok {
+			continue
+		}
+		limit, err := fallbackRateLimit(name, raw)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "WARN: ignoring %s: %v\n", name, err)
+			return core.RateLimit{}, nil
+		}
+		return limit, nil
+	}
+	return core.RateLimit{}, nil
+}
+
+func fallbackRateLimit(name string, raw any) (core.RateLimit, error) {
+	switch value := raw.(type) {
+	case string:
+		rate, err := core.ParseRate(value)
+		return core.RateLimit{Rate: rate}, err
+	case float64:
+		if value <= 0 {
+			return core.RateLimit{}, fmt.Errorf("%s must be a positive number of requests per second", name)
+		}
+		return core.RateLimit{Rate: value}, nil
+	case map[string]any:
+		return rateLimitObject(name, value)
+	}
+	return core.RateLimit{}, fmt.Errorf("%s must be a rate such as 100/m, a number of requests per second or an object", name)
+}
+
+// rateLimitObject parses the keys of an x-mcp-rate-limit object found under
+// the extension name.
+func rateLimitObject(name string, values map[string]any) (core.RateLimit, error) {
+	var limit core.RateLimit
+	for key, value := range values {
+		switch key {
+		case "rate":
+			s, ok := value.(string)
+			if !ok {
+				return limit, fmt.Errorf("%s.rate must be a string such as 10/s", name)
+			}
+			rate, err := core.ParseRate(s)
+			if err != nil {
+				return limit, fmt.Errorf("%s.rate: %v", name, err)
+			}
+			limit.Rate = rate
+		case "burst", "concurrency":
+			n, ok := value.(float64)
+			if !ok || n < 0 || n != math.Trunc(n) {
+				return limit, fmt.Errorf("%s.%s must be a non-negative integer", name, key)
+			}
+			if key == "burst" {
+				limit.Burst = int(n)
+			} else {
+				limit.Concurrency = int(n)
+			}
+		default:
+			return limit, fmt.Errorf("unknown key %s.%s, expected one of rate, burst, concurrency", name, key)
+		}
+	}
+	if limit.Burst > 0 && limit.Rate == 0 {
+		return limit, fmt.Errorf("%s.burst needs a rate", name)
+	}
+	return limit, nil
+}
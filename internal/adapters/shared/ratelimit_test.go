@@ -0,0 +1,84 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+func TestConvertRateLimit(t *testing.T) {
+	data, err := convertYAML(t, `
+openapi: 3.0.3
+info: {title: Reports, version: 1.0.0}
+x-mcp-rate-limit: {rate: 600/m, burst: 20, concurrency: 4}
+paths:
+  /reports:
+    post:
+      x-mcp-rate-limit: {rate: 1/s}
+    get: {}
+`)
+	require.NoError(t, err)
+	assert.Equal(t, core.RateLimit{Rate: 10, Burst: 20, Concurrency: 4}, data.Upstreams[0].RateLimit)
+	assert.Equal(t, core.RateLimit{}, data.Tools[0].RateLimit)
+	assert.Equal(t, core.RateLimit{Rate: 1}, data.Tools[1].RateLimit)
+}
+
+func TestExtRateLimitValue(t *testing.T) {
+	limit, err := extRateLimitValue(map[string]any{extRateLimit: map[string]any{"rate": "3600/h", "concurrency": 2.0}})
+	require.NoError(t, err)
+	assert.Equal(t, core.RateLimit{Rate: 1, Concurrency: 2}, limit)
+	assert.True(t, limit.Set())
+
+	limit, err = extRateLimitValue(nil)
+	require.NoError(t, err)
+	assert.False(t, limit.Set())
+
+	for want, ext := range map[string]any{
+		"must be an object":          "10/s",
+		"expected requests per":      map[string]any{"rate": "10 per second"},
+		"must be a string":           map[string]any{"rate": 10.0},
+		"must be a non-negative int": map[string]any{"concurrency": 1.5},
+		"unknown key":                map[string]any{"limit": 1.0},
+		"burst needs a rate":         map[string]any{"burst": 5.0},
+	} {
+		_, err := extRateLimitValue(map[string]any{extRateLimit: ext})
+		assert.ErrorContains(t, err, want)
+	}
+}
+
+func TestExtRateLimitFallbacks(t *testing.T) {
+	for _, tt := range []struct {
+		extensions map[string]any
+		want       core.RateLimit
+	}{
+		{map[string]any{"x-ratelimit-limit": "120/m"}, core.RateLimit{Rate: 2}},
+		{map[string]any{"x-rate-limit": 5.0}, core.RateLimit{Rate: 5}},
+		{map[string]any{"x-ratelimit": map[string]any{"rate": "10/s", "burst": 20.0}}, core.RateLimit{Rate: 10, Burst: 20}},
+		// ours wins, then the fallbacks in order
+		{map[string]any{extRateLimit: map[string]any{"rate": "1/s"}, "x-ratelimit": "10/s"}, core.RateLimit{Rate: 1}},
+		{map[string]any{"x-ratelimit-limit": "60/s", "x-ratelimit": "10/s"}, core.RateLimit{Rate: 10}},
+		// shapes of other tools are ignored
+		{map[string]any{"x-ratelimit": map[string]any{"limit": 100.0, "period": 60.0}}, core.RateLimit{}},
+		{map[string]any{"x-ratelimit-limit": "100 per minute"}, core.RateLimit{}},
+		{map[string]any{"x-rate-limit": 0.0}, core.RateLimit{}},
+	} {
+		limit, err := extRateLimitValue(tt.extensions)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, limit, tt.extensions)
+	}
+
+	data, err := convertYAML(t, `
+openapi: 3.0.3
+info: {title: Reports, version: 1.0.0}
+x-ratelimit-limit: 600/m
+paths:
+  /reports:
+    get:
+      x-rate-limit: 1
+`)
+	require.NoError(t, err)
+	assert.Equal(t, core.RateLimit{Rate: 10}, data.Upstreams[0].RateLimit)
+	assert.Equal(t, core.RateLimit{Rate: 1}, data.Tools[0].RateLimit)
+}
@@ -0,0 +1,387 @@
+package shared
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// resolver converts OpenAPI schemas into self-contained JSON Schema. It
+// flattens allOf into a single object where possible, keeps oneOf and anyOf
+// as unions, pins the discriminator property of each tagged variant and
+// moves recursive schemas into $defs, referenced with "#/$defs/<name>".
+//
+// Use one resolver per JSON Schema document: the $defs collected while
+// converting belong at the root of the document the schemas end up in.
+type resolver struct {
+	defs      map[string]any
+	names     map[*openapi3.Schema]string
+	visiting  map[*openapi3.Schema]bool
+	recursive map[*openapi3.Schema]bool
+}
+
+func newResolver() *resolver {
+	return &resolver{
+		defs:      make(map[string]any),
+		names:     make(map[*openapi3.Schema]string),
+		visiting:  make(map[*openapi3.Schema]bool),
+		recursive: make(map[*openapi3.Schema]bool),
+	}
+}
+
+// root converts the top-level schema of a document. Unlike schema it is
+// always inlined, even when it is recursive, so the document keeps its type.
+// The collected $defs are attached to the result.
+func (r *resolver) root(ref *openapi3.SchemaRef) map[string]any {
+	return r.withDefs(r.resolve(ref, true))
+}
+
+// withDefs attaches the collected $defs to document, which must be the root
+// of the JSON Schema document the resolver converted schemas for.
+func (r *resolver) withDefs(document map[string]any) map[string]any {
+	if len(r.defs) > 0 {
+		document["$defs"] = r.defs
+	}
+	return document
+}
+
+// schema converts a nested schema. A schema that refers back to itself is
+// replaced by a reference into $defs.
+func (r *resolver) schema(ref *openapi3.SchemaRef) map[string]any {
+	return r.resolve(ref, false)
+}
+
+func (r *resolver) resolve(ref *openapi3.SchemaRef, inline bool) map[string]any {
+	if ref == nil || ref.Value == nil {
+		return map[string]any{}
+	}
+	s := ref.Value
+	if r.visiting[s] {
+		r.recursive[s] = true
+		return defRef(r.defName(s, ref.Ref))
+	}
+	if name, ok := r.names[s]; ok && !inline {
+		// converted before and known to be recursive
+		return defRef(name)
+	}
+
+	r.visiting[s] = true
+	out := r.convert(s)
+	delete(r.visiting, s)
+
+	if !r.recursive[s] {
+		return out
+	}
+	name := r.defName(s, ref.Ref)
+	r.defs[name] = out
+	if inline {
+		return copyMap(out)
+	}
+	return defRef(name)
+}
+
+// within runs convert with ref on the stack. Use it when the schemas
+// nested in ref are converted one by one, such as the properties of a
+// request body turned into arguments, so those referring back to ref
+// become references to its $defs entry.
+func (r *resolver) within(ref *openapi3.SchemaRef, convert func()) {
+	if ref == nil || ref.Value == nil {
+		convert()
+		return
+	}
+	s := ref.Value
+	r.visiting[s] = true
+	convert()
+	if r.recursive[s] {
+		r.defs[r.defName(s, ref.Ref)] = r.convert(s)
+	}
+	delete(r.visiting, s)
+}
+
+func defRef(name string) map[string]any {
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+// defName returns the $defs key of s, named after its component when it
+// has one.
+func (r *resolver) defName(s *openapi3.Schema, ref string) string {
+	if name, ok := r.names[s]; ok {
+		return name
+	}
+	base := "Schema"
+	if i := strings.LastIndex(ref, "/"); i >= 0 && i < len(ref)-1 {
+		base = ref[i+1:]
+	}
+	taken := make(map[string]bool, len(r.names))
+	for _, name := range r.names {
+		taken[name] = true
+	}
+	name := base
+	for n := 2; taken[name]; n++ {
+		name = fmt.Sprintf("%s%d", base, n)
+	}
+	r.names[s] = name
+	return name
+}
+
+func (r *resolver) convert(s *openapi3.Schema) map[string]any {
+	out := make(map[string]any)
+	if len(s.AllOf) > 0 {
+		if flat, ok := r.flatten(s); ok {
+			s = flat
+		} else {
+			parts := make([]any, len(s.AllOf))
+			for i, part := range s.AllOf {
+				parts[i] = r.schema(part)
+			}
+			out["allOf"] = parts
+		}
+	}
+
+	if s.Type != nil && len(*s.Type) > 0 {
+		types := append([]string(nil), s.Type.Slice()...)
+		if s.Nullable && !contains(types, "null") {
+			types = append(types, "null")
+		}
+		if len(types) == 1 {
+			out["type"] = types[0]
+		} else {
+			out["type"] = types
+		}
+	}
+	if s.Title != "" {
+		out["title"] = s.Title
+	}
+	if s.Description != "" {
+		out["description"] = s.Description
+	}
+	if s.Format != "" {
+		out["format"] = s.Format
+	}
+	if len(s.Enum) > 0 {
+		out["enum"] = s.Enum
+	}
+	if s.Default != nil {
+		out["default"] = s.Default
+	}
+	if s.Example != nil {
+		out["examples"] = []any{s.Example}
+	}
+	if s.Deprecated {
+		out["deprecated"] = true
+	}
+	if s.ReadOnly {
+		out["readOnly"] = true
+	}
+	if s.WriteOnly {
+		out["writeOnly"] = true
+	}
+	if s.Min != nil {
+		if s.ExclusiveMin {
+			out["exclusiveMinimum"] = *s.Min
+		} else {
+			out["minimum"] = *s.Min
+		}
+	}
+	if s.Max != nil {
+		if s.ExclusiveMax {
+			out["exclusiveMaximum"] = *s.Max
+		} else {
+			out["maximum"] = *s.Max
+		}
+	}
+	if s.MultipleOf != nil {
+		out["multipleOf"] = *s.MultipleOf
+	}
+	if s.MinLength > 0 {
+		out["minLength"] = s.MinLength
+	}
+	if s.MaxLength != nil {
+		out["maxLength"] = *s.MaxLength
+	}
+	if s.Pattern != "" {
+		out["pattern"] = s.Pattern
+	}
+	if s.Items != nil {
+		out["items"] = r.schema(s.Items)
+	}
+	if s.MinItems > 0 {
+		out["minItems"] = s.MinItems
+	}
+	if s.MaxItems != nil {
+		out["maxItems"] = *s.MaxItems
+	}
+	if s.UniqueItems {
+		out["uniqueItems"] = true
+	}
+	if len(s.Properties) > 0 {
+		properties := make(map[string]any, len(s.Properties))
+		for _, name := range sortedKeys(s.Properties) {
+			properties[name] = r.schema(s.Properties[name])
+		}
+		out["properties"] = properties
+	}
+	if len(s.Required) > 0 {
+		out["required"] = s.Required
+	}
+	if s.MinProps > 0 {
+		out["minProperties"] = s.MinProps
+	}
+	if s.MaxProps != nil {
+		out["maxProperties"] = *s.MaxProps
+	}
+	if s.AdditionalProperties.Schema != nil {
+		out["additionalProperties"] = r.schema(s.AdditionalProperties.Schema)
+	} else if s.AdditionalProperties.Has != nil {
+		out["additionalProperties"] = *s.AdditionalProperties.Has
+	}
+	if len(s.OneOf) > 0 {
+		out["oneOf"] = r.variants(s.OneOf, s.Discriminator)
+	}
+	if len(s.AnyOf) > 0 {
+		out["anyOf"] = r.variants(s.AnyOf, s.Discriminator)
+	}
+	if s.Not != nil {
+		out["not"] = r.schema(s.Not)
+	}
+	return out
+}
+
+// flatten merges the allOf parts of an object schema into a copy of s.
+// Parts that are not objects, or that are being resolved further up the
+// stack, cannot be merged and leave allOf in place.
+func (r *resolver) flatten(s *openapi3.Schema) (*openapi3.Schema, bool) {
+	flat := *s
+	flat.AllOf = nil
+	flat.Properties = make(openapi3.Schemas, len(s.Properties))
+	for name, prop := range s.Properties {
+		flat.Properties[name] = prop
+	}
+	flat.Required = append([]string(nil), s.Required...)
+
+	for _, part := range s.AllOf {
+		if part == nil || part.Value == nil {
+			continue
+		}
+		p := part.Value
+		if r.visiting[p] {
+			return nil, false
+		}
+		if len(p.AllOf) > 0 {
+			r.visiting[p] = true
+			merged, ok := r.flatten(p)
+			delete(r.visiting, p)
+			if !ok {
+				return nil, false
+			}
+			p = merged
+		}
+		if p.Type != nil && !p.Type.Is("object") {
+			return nil, false
+		}
+		if len(p.OneOf) > 0 || len(p.AnyOf) > 0 {
+			if len(flat.OneOf) > 0 || len(flat.AnyOf) > 0 {
+				return nil, false
+			}
+			flat.OneOf, flat.AnyOf, flat.Discriminator = p.OneOf, p.AnyOf, p.Discriminator
+		}
+		if flat.Type == nil {
+			flat.Type = p.Type
+		}
+		if flat.Description == "" {
+			flat.Description = p.Description
+		}
+		for name, prop := range p.Properties {
+			if _, ok := flat.Properties[name]; !ok {
+				flat.Properties[name] = prop
+			}
+		}
+		for _, name := range p.Required {
+			if !contains(flat.Required, name) {
+				flat.Required = append(flat.Required, name)
+			}
+		}
+		if flat.AdditionalProperties.Schema == nil && flat.AdditionalProperties.Has == nil {
+			flat.AdditionalProperties = p.AdditionalProperties
+		}
+	}
+	if flat.Type == nil && len(flat.Properties) > 0 {
+		flat.Type = &openapi3.Types{"object"}
+	}
+	return &flat, true
+}
+
+// variants converts the members of a oneOf or anyOf. When a discriminator
+// applies, each member it can name gets its discriminator property pinned
+// to the value selecting it, so the model knows which value to send.
+func (r *resolver) variants(refs openapi3.SchemaRefs, disc *openapi3.Discriminator) []any {
+	tags := discriminatorValues(refs, disc)
+	out := make([]any, len(refs))
+	for i, ref := range refs {
+		variant := r.schema(ref)
+		if tag, ok := tags[i]; ok {
+			variant = tagVariant(variant, disc.PropertyName, tag)
+		}
+		out[i] = variant
+	}
+	return out
+}
+
+// discriminatorValues maps the index of every variant to the discriminator
+// value selecting it: its explicit mapping, or else its component name.
+func discriminatorValues(refs openapi3.SchemaRefs, disc *openapi3.Discriminator) map[int]string {
+	if disc == nil || disc.PropertyName == "" {
+		return nil
+	}
+	tags := make(map[int]string)
+	for i, ref := range refs {
+		if ref == nil || ref.Ref == "" {
+			continue
+		}
+		name := ref.Ref[strings.LastIndex(ref.Ref, "/")+1:]
+		tags[i] = name
+		for _, value := range sortedKeys(disc.Mapping) {
+			target := disc.Mapping[value]
+			if target == ref.Ref || target == name || strings.HasSuffix(ref.Ref, "/"+strings.TrimPrefix(target, "#/")) {
+				tags[i] = value
+				break
+			}
+		}
+	}
+	return tags
+}
+
+func tagVariant(variant map[string]any, property, value string) map[string]any {
+	pin := map[string]any{"const": value}
+	if _, ok := variant["$ref"]; ok {
+		return map[string]any{"allOf": []any{variant, map[string]any{
+			"properties": map[string]any{property: pin},
+			"required":   []string{property},
+		}}}
+	}
+	tagged := copyMap(variant)
+	properties, _ := tagged["properties"].(map[string]any)
+	properties = copyMap(properties)
+	if existing, ok := properties[property].(map[string]any); ok {
+		pinned := copyMap(existing)
+		pinned["const"] = value
+		delete(pinned, "enum")
+		pin = pinned
+	}
+	properties[property] = pin
+	tagged["properties"] = properties
+	required, _ := tagged["required"].([]string)
+	if !contains(required, property) {
+		tagged["required"] = append(append([]string(nil), required...), property)
+	}
+	return tagged
+}
+
+func copyMap(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
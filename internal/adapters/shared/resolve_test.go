@@ -0,0 +1,215 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+func convertFile(t *testing.T, name string) map[string]core.Tool {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromFile("../../../testdata/" + name)
+	require.NoError(t, err)
+	data, err := Convert(doc)
+	require.NoError(t, err)
+	tools := make(map[string]core.Tool)
+	for _, tool := range data.Tools {
+		tools[tool.Name] = tool
+	}
+	return tools
+}
+
+func TestResolveAllOf(t *testing.T) {
+	tools := convertFile(t, "petstore-expanded.yaml")
+
+	pet := map[string]any{
+		"type":     "object",
+		"required": []string{"name", "id"},
+		"properties": map[string]any{
+			"id":   map[string]any{"type": "integer", "format": "int64"},
+			"name": map[string]any{"type": "string"},
+			"tag":  map[string]any{"type": "string"},
+		},
+	}
+	assert.Equal(t, pet, tools["post_pets"].OutputSchema)
+
+	// the properties of every allOf part become arguments
+	replace := tools["put_pets_by_id"]
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id":      map[string]any{"type": "integer", "format": "int64", "description": "ID of pet to replace"},
+			"body_id": map[string]any{"type": "integer", "format": "int64"},
+			"name":    map[string]any{"type": "string"},
+			"tag":     map[string]any{"type": "string"},
+		},
+		"required": []string{"id", "body_id", "name"},
+	}, replace.InputSchema)
+
+	list := tools["get_pets"]
+	assert.Equal(t, map[string]any{
+		"type":        "array",
+		"items":       map[string]any{"type": "string"},
+		"description": "tags to filter by",
+	}, list.InputSchema["properties"].(map[string]any)["tags"])
+}
+
+func TestResolveDiscriminator(t *testing.T) {
+	tools := convertFile(t, "pets-discriminator.yaml")
+
+	register := tools["post_pets"]
+	require.Len(t, register.Arguments, 1)
+	body := register.Arguments[0]
+	assert.Equal(t, core.InRawBody, body.In)
+	assert.True(t, body.Required)
+
+	variants, ok := body.Schema["oneOf"].([]any)
+	require.True(t, ok)
+	require.Len(t, variants, 3)
+	var tags []any
+	for _, v := range variants {
+		variant := v.(map[string]any)
+		assert.Contains(t, variant["required"], "petType")
+		tags = append(tags, variant["properties"].(map[string]any)["petType"].(map[string]any)["const"])
+	}
+	// mapped values win over component names
+	assert.Equal(t, []any{"Cat", "dog", "Lizard"}, tags)
+	assert.Equal(t, "A representation of a cat", variants[0].(map[string]any)["description"])
+	assert.Equal(t, map[string]any{"type": "string", "readOnly": true}, variants[0].(map[string]any)["properties"].(map[string]any)["id"])
+
+	owner := tools["patch_pets_by_id_owner"]
+	assert.Equal(t, map[string]any{"anyOf": []any{
+		map[string]any{"type": "string", "format": "email"},
+		map[string]any{"type": "string", "pattern": `^\+[0-9]+$`},
+	}}, owner.InputSchema["properties"].(map[string]any)["contact"])
+}
+
+func TestResolveRecursive(t *testing.T) {
+	tools := convertFile(t, "tree.yaml")
+	replace := tools["put_categories"]
+
+	children := map[string]any{"type": "array", "items": map[string]any{"$ref": "#/$defs/Category"}}
+	category := map[string]any{
+		"type":     "object",
+		"required": []string{"name"},
+		"properties": map[string]any{
+			"name":     map[string]any{"type": "string"},
+			"children": children,
+		},
+	}
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":     map[string]any{"type": "string"},
+			"children": children,
+		},
+		"required": []string{"name"},
+		"$defs":    map[string]any{"Category": category},
+	}, replace.InputSchema)
+	assert.Equal(t, map[string]any{
+		"type":       "object",
+		"required":   []string{"name"},
+		"properties": category["properties"],
+		"$defs":      map[string]any{"Category": category},
+	}, replace.OutputSchema)
+}
+
+func TestResolveSchema(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Test, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Id:
+      allOf:
+        - {type: string, minLength: 1}
+        - {type: string, maxLength: 36}
+    Price:
+      type: number
+      minimum: 0
+      exclusiveMinimum: true
+      multipleOf: 0.01
+      example: 9.99
+    Shape:
+      oneOf:
+        - $ref: '#/components/schemas/Circle'
+        - $ref: '#/components/schemas/Group'
+      discriminator:
+        propertyName: kind
+    Circle:
+      type: object
+      properties:
+        kind: {type: string, enum: [Circle, Group]}
+        radius: {type: number}
+    Group:
+      type: object
+      properties:
+        kind: {type: string}
+        shapes:
+          type: array
+          items: {$ref: '#/components/schemas/Shape'}
+    NotNull:
+      not: {type: 'null'}
+`))
+	require.NoError(t, err)
+	schemas := doc.Components.Schemas
+
+	tests := []struct {
+		name string
+		want map[string]any
+	}{
+		{"Id", map[string]any{"allOf": []any{
+			map[string]any{"type": "string", "minLength": uint64(1)},
+			map[string]any{"type": "string", "maxLength": uint64(36)},
+		}}},
+		{"Price", map[string]any{"type": "number", "exclusiveMinimum": 0.0, "multipleOf": 0.01, "examples": []any{9.99}}},
+		{"NotNull", map[string]any{"not": map[string]any{"type": "null"}}},
+		{"Shape", map[string]any{
+			"oneOf": []any{
+				map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"kind":   map[string]any{"type": "string", "const": "Circle"},
+						"radius": map[string]any{"type": "number"},
+					},
+					"required": []string{"kind"},
+				},
+				map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"kind":   map[string]any{"type": "string", "const": "Group"},
+						"shapes": map[string]any{"type": "array", "items": map[string]any{"$ref": "#/$defs/Shape"}},
+					},
+					"required": []string{"kind"},
+				},
+			},
+			"$defs": map[string]any{"Shape": map[string]any{"oneOf": []any{
+				map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"kind":   map[string]any{"type": "string", "const": "Circle"},
+						"radius": map[string]any{"type": "number"},
+					},
+					"required": []string{"kind"},
+				},
+				map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"kind":   map[string]any{"type": "string", "const": "Group"},
+						"shapes": map[string]any{"type": "array", "items": map[string]any{"$ref": "#/$defs/Shape"}},
+					},
+					"required": []string{"kind"},
+				},
+			}}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newResolver().root(schemas[tt.name]))
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package shared
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// isObject reports whether ref describes a JSON object, by type or by
+// declaring properties.
+func isObject(ref *openapi3.SchemaRef) bool {
+	return isObjectSchema(flatSchema(ref))
+}
+
+func isObjectSchema(s *openapi3.Schema) bool {
+	if s == nil {
+		return false
+	}
+	return s.Type.Is("object") || (s.Type == nil && len(s.Properties) > 0)
+}
+
+// flatSchema returns the schema of ref with its allOf parts merged in, or
+// the schema itself when they cannot be merged.
+func flatSchema(ref *openapi3.SchemaRef) *openapi3.Schema {
+	if ref == nil || ref.Value == nil {
+		return nil
+	}
+	if len(ref.Value.AllOf) > 0 {
+		if flat, ok := newResolver().flatten(ref.Value); ok {
+			return flat
+		}
+	}
+	return ref.Value
+}
+
+// isBinary reports whether ref is a string of raw bytes, such as a file.
+func isBinary(ref *openapi3.SchemaRef) bool {
+	return ref != nil && ref.Value != nil && ref.Value.Type.Is("string") && ref.Value.Format == "binary"
+}
+
+// RequestContentType picks the request body encoding the generated server
+// sends: JSON if declared, then form encodings, then whatever comes first.
+func RequestContentType(content openapi3.Content) string {
+	types := sortedKeys(content)
+	for _, prefer := range []func(string) bool{
+		IsJSON,
+		func(t string) bool { return mediaType(t) == "application/x-www-form-urlencoded" },
+		func(t string) bool { return strings.HasPrefix(mediaType(t), "multipart/") },
+	} {
+		for _, contentType := range types {
+			if prefer(contentType) {
+				return contentType
+			}
+		}
+	}
+	if len(types) == 0 {
+		return ""
+	}
+	return types[0]
+}
+
+func mediaType(contentType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+}
+
+// IsJSON reports whether contentType carries JSON, including vendor types
+// such as application/problem+json.
+func IsJSON(contentType string) bool {
+	mediaType := mediaType(contentType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// isText reports whether a response of contentType is returned to the model
+// as text rather than as image or binary content.
+func isText(contentType string) bool {
+	mediaType := mediaType(contentType)
+	switch mediaType {
+	case "application/xml", "application/yaml", "application/x-yaml", "application/javascript", "application/x-www-form-urlencoded":
+		return true
+	}
+	return strings.HasPrefix(mediaType, "text/") || IsJSON(mediaType) || strings.HasSuffix(mediaType, "+xml")
+}
+
+// responseTypes lists the content types of the 2xx responses, JSON types
+// first so they are preferred when sent as the Accept header.
+func responseTypes(responses *openapi3.Responses) []string {
+	if responses == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var types []string
+	for status, resp := range responses.Map() {
+		if !strings.HasPrefix(status, "2") || resp == nil || resp.Value == nil {
+			continue
+		}
+		for contentType := range resp.Value.Content {
+			if !seen[contentType] {
+				seen[contentType] = true
+				types = append(types, contentType)
+			}
+		}
+	}
+	sort.Strings(types)
+	sort.SliceStable(types, func(i, j int) bool { return IsJSON(types[i]) && !IsJSON(types[j]) })
+	return types
+}
+
+// outputSchema derives the MCP output schema of an operation from its
+// success responses. It returns nil unless every 2xx response is JSON with a
+// schema, since the tool must then return structured content for every
+// successful call. Differing schemas of several 2xx responses are combined
+// with anyOf. MCP requires an object schema; any other schema is wrapped as
+// the "result" property and wrap is true.
+func outputSchema(responses *openapi3.Responses) (schema map[string]any, wrap bool) {
+	if responses == nil {
+		return nil, false
+	}
+	// one resolver for all responses, so their $defs end up in one place
+	r := newResolver()
+	var variants []any
+	object := true
+	for _, status := range sortedKeys(responses.Map()) {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		resp := responses.Map()[status]
+		if resp == nil || resp.Value == nil {
+			return nil, false
+		}
+		var found *openapi3.SchemaRef
+		for _, contentType := range sortedKeys(resp.Value.Content) {
+			media := resp.Value.Content[contentType]
+			if IsJSON(contentType) && media.Schema != nil && media.Schema.Value != nil {
+				found = media.Schema
+				break
+			}
+		}
+		if found == nil {
+			return nil, false
+		}
+		variant := r.resolve(found, true)
+		if !slices.ContainsFunc(variants, func(v any) bool { return reflect.DeepEqual(v, variant) }) {
+			variants = append(variants, variant)
+			object = object && isObject(found) && !found.Value.Nullable
+		}
+	}
+	if len(variants) == 0 {
+		return nil, false
+	}
+
+	schema = variants[0].(map[string]any)
+	if len(variants) > 1 {
+		schema = map[string]any{"anyOf": variants}
+	}
+	if object {
+		if len(variants) > 1 {
+			schema["type"] = "object"
+		}
+		return r.withDefs(schema), false
+	}
+	// $defs must stay at the root of the document
+	return r.withDefs(map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"result": schema},
+		"required":   []string{"result"},
+	}), true
+}
@@ -0,0 +1,213 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputSchema(t *testing.T) {
+	data, err := convertYAML(t, `
+openapi: 3.0.0
+info: {title: Test, version: 1.0.0}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, description: Pet name}
+        tag: {type: string, nullable: true}
+        parent: {$ref: '#/components/schemas/Pet'}
+paths:
+  /pets/{id}:
+    get:
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+        '404':
+          description: missing
+    delete:
+      responses:
+        '204': {description: deleted}
+  /pets:
+    get:
+      responses:
+        '200':
+          description: ok
+          content:
+            application/hal+json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Pet'}
+    post:
+      responses:
+        '201':
+          description: created
+          content:
+            text/plain:
+              schema: {type: string}
+`)
+	require.NoError(t, err)
+	tools := make(map[string]int)
+	for i, tool := range data.Tools {
+		tools[tool.Name] = i
+	}
+	require.Len(t, tools, 4)
+
+	pet := map[string]any{
+		"type":     "object",
+		"required": []string{"name"},
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string", "description": "Pet name"},
+			"tag":  map[string]any{"type": []string{"string", "null"}},
+			// the cycle refers back to the hoisted schema
+			"parent": map[string]any{"$ref": "#/$defs/Pet"},
+		},
+	}
+	defs := map[string]any{"Pet": pet}
+	get := data.Tools[tools["get_pets_by_id"]]
+	assert.Equal(t, map[string]any{
+		"type":       "object",
+		"required":   []string{"name"},
+		"properties": pet["properties"],
+		"$defs":      defs,
+	}, get.OutputSchema)
+	assert.False(t, get.WrapOutput)
+
+	list := data.Tools[tools["get_pets"]]
+	assert.Equal(t, map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"result": map[string]any{"type": "array", "items": map[string]any{"$ref": "#/$defs/Pet"}}},
+		"required":   []string{"result"},
+		"$defs":      defs,
+	}, list.OutputSchema)
+	assert.True(t, list.WrapOutput)
+
+	assert.Nil(t, data.Tools[tools["delete_pets_by_id"]].OutputSchema)
+	assert.Nil(t, data.Tools[tools["post_pets"]].OutputSchema)
+}
+
+func TestOutputSchemaSeveralResponses(t *testing.T) {
+	data, err := convertYAML(t, `
+openapi: 3.0.0
+info: {title: Test, version: 1.0.0}
+components:
+  schemas:
+    Job:
+      type: object
+      properties:
+        id: {type: string}
+paths:
+  /jobs:
+    post:
+      responses:
+        '200':
+          description: done
+          content:
+            application/json:
+              schema: {type: object, properties: {result: {type: string}}}
+        '202':
+          description: queued
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Job'}
+        '201':
+          description: queued too
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Job'}
+  /jobs/{id}:
+    get:
+      responses:
+        '200':
+          description: done
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Job'}
+        '206':
+          description: partial
+          content:
+            application/json:
+              schema: {type: array, items: {type: string}}
+`)
+	require.NoError(t, err)
+	tools := make(map[string]int)
+	for i, tool := range data.Tools {
+		tools[tool.Name] = i
+	}
+	job := map[string]any{"type": "object", "properties": map[string]any{"id": map[string]any{"type": "string"}}}
+
+	// objects stay unwrapped, the same schema is listed once
+	post := data.Tools[tools["post_jobs"]]
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"anyOf": []any{
+			map[string]any{"type": "object", "properties": map[string]any{"result": map[string]any{"type": "string"}}},
+			job,
+		},
+	}, post.OutputSchema)
+	assert.False(t, post.WrapOutput)
+
+	get := data.Tools[tools["get_jobs_by_id"]]
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{"result": map[string]any{"anyOf": []any{
+			job,
+			map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		}}},
+		"required": []string{"result"},
+	}, get.OutputSchema)
+	assert.True(t, get.WrapOutput)
+}
+
+func TestIsJSON(t *testing.T) {
+	assert.True(t, IsJSON("application/json"))
+	assert.True(t, IsJSON("application/json; charset=utf-8"))
+	assert.True(t, IsJSON("application/problem+json"))
+	assert.False(t, IsJSON("text/plain"))
+	assert.False(t, IsJSON("application/jsonl"))
+}
+
+func TestResponseTypes(t *testing.T) {
+	data, err := convertYAML(t, `
+openapi: 3.0.0
+info: {title: Test, version: 1.0.0}
+paths:
+  /pet/{petId}/image:
+    get:
+      responses:
+        '200':
+          description: ok
+          content:
+            image/png: {}
+            image/jpeg: {}
+            application/json:
+              schema: {type: object}
+        '206':
+          description: partial
+          content:
+            image/png: {}
+        '404':
+          description: missing
+          content:
+            application/problem+json: {}
+`)
+	require.NoError(t, err)
+	require.Len(t, data.Tools, 1)
+	assert.Equal(t, []string{"application/json", "image/jpeg", "image/png"}, data.Tools[0].ResponseTypes)
+	assert.Nil(t, data.Tools[0].OutputSchema)
+}
+
+func TestIsText(t *testing.T) {
+	for _, contentType := range []string{"text/csv", "application/json", "application/vnd.api+json", "application/xml", "application/atom+xml", "application/yaml"} {
+		assert.True(t, isText(contentType), contentType)
+	}
+	for _, contentType := range []string{"image/png", "application/pdf", "application/octet-stream", "audio/mpeg"} {
+		assert.False(t, isText(contentType), contentType)
+	}
+}
@@ -0,0 +1,555 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/shared"
+	"github.com/xxlv/ai-create-mcp/internal/lint"
+)
+
+// Config holds generator settings loaded from a TOML file. Command-line
+// flags take precedence over values set here.
+type Config struct {
+	// PackageManager selects the Python toolchain: uv, poetry or pip.
+	PackageManager string `toml:"package_manager"`
+	// Offline writes dependencies into pyproject.toml instead of asking the
+	// package manager to resolve and install them.
+	Offline bool `toml:"offline"`
+	// MaxBinarySize caps the size in bytes of image and binary responses
+	// returned by the generated server.
+	MaxBinarySize int64 `toml:"max_binary_size"`
+	// MaxResponseSize caps the size in bytes of text responses returned by
+	// the generated server. Longer ones are cut.
+	MaxResponseSize int64 `toml:"max_response_size"`
+	// FieldsArgument adds a fields argument to every tool returning JSON,
+	// selecting the parts of the response returned to the model.
+	FieldsArgument bool `toml:"fields_argument"`
+	// Timeout is how long an upstream request may take, e.g. "30s".
+	Timeout time.Duration `toml:"timeout"`
+	// Retries is how often a failed upstream request is retried, nil for
+	// the default.
+	Retries *int `toml:"retries"`
+	// RetryBackoff is the wait before the first retry, e.g. "500ms",
+	// doubled for each further one.
+	RetryBackoff time.Duration `toml:"retry_backoff"`
+	// RateLimit is the default rate limit of every upstream whose spec or
+	// source sets none.
+	RateLimit *RateLimit `toml:"rate_limit"`
+	// AllowedHosts restricts the hosts the generated server sends requests
+	// to, such as "api.example.com" or "*.example.com". By default it may
+	// only reach the hosts of the upstream base URLs.
+	AllowedHosts []string `toml:"allowed_hosts"`
+	// ToolMode is static to expose every operation as a tool, or dynamic
+	// to expose search, describe and invoke meta-tools instead.
+	ToolMode string `toml:"tool_mode"`
+	// TokenBudget fails generation when the estimated size of the tool
+	// list exceeds this many tokens. 0 disables the check.
+	TokenBudget int `toml:"token_budget"`
+	// Lint configures the rules of the lint command.
+	Lint lint.Config `toml:"lint"`
+	// Tools overrides the conversion result of individual tools, keyed by
+	// tool name.
+	Tools map[string]ToolConfig `toml:"tools"`
+	// AutoPrompts set to false drops the prompts generated for every GET
+	// operation, keeping those asked for with x-mcp-prompt or Prompts.
+	AutoPrompts *bool `toml:"auto_prompts"`
+	// Prompts overrides or adds prompts, keyed by prompt name.
+	Prompts map[string]PromptConfig `toml:"prompts"`
+	// Completions completes prompt arguments and resource template
+	// variables with values looked up by calling a tool, keyed by name.
+	Completions map[string]CompletionConfig `toml:"completions"`
+	// Sources lists the specs merged into the generated server, in addition
+	// to those given with -oaspath.
+	Sources []SourceConfig `toml:"sources"`
+}
+
+// SourceConfig is one spec merged into the generated server.
+type SourceConfig struct {
+	// Name identifies the upstream; derived from the spec file name when
+	// empty.
+	Name string `toml:"name"`
+	// Spec is the path or URL of the OpenAPI document.
+	Spec string `toml:"spec"`
+	// Prefix is prepended to the tool, prompt and resource names.
+	Prefix string `toml:"prefix"`
+	// BaseURL replaces the servers declared by the spec.
+	BaseURL string `toml:"base_url"`
+	// Overlays are applied to this spec only, in order.
+	Overlays  []string   `toml:"overlays"`
+	Auth      *Auth      `toml:"auth"`
+	RateLimit *RateLimit `toml:"rate_limit"`
+}
+
+// RateLimit caps the requests a generated server sends.
+type RateLimit struct {
+	// Rate is a number of requests per second, minute or hour such as
+	// "10/s" or "100/m".
+	Rate string `toml:"rate"`
+	// Burst is the number of requests sent at once before Rate applies.
+	Burst int `toml:"burst"`
+	// Concurrency caps the requests in flight at the same time.
+	Concurrency int `toml:"concurrency"`
+}
+
+// Limit converts the limit, which Load has validated.
+func (r *RateLimit) Limit() core.RateLimit {
+	if r == nil {
+		return core.RateLimit{}
+	}
+	limit := core.RateLimit{Burst: r.Burst, Concurrency: r.Concurrency}
+	if r.Rate != "" {
+		limit.Rate, _ = core.ParseRate(r.Rate)
+	}
+	return limit
+}
+
+func (r *RateLimit) validate() error {
+	if r == nil {
+		return nil
+	}
+	if r.Rate != "" {
+		if _, err := core.ParseRate(r.Rate); err != nil {
+			return err
+		}
+	}
+	if r.Burst < 0 || r.Concurrency < 0 {
+		return fmt.Errorf("burst and concurrency must not be negative")
+	}
+	if r.Burst > 0 && r.Rate == "" {
+		return fmt.Errorf("burst needs a rate")
+	}
+	return nil
+}
+
+// Auth configures the credential sent to one upstream.
+type Auth struct {
+	// Type is bearer, header, query or none.
+	Type string `toml:"type"`
+	// Name is the header or query parameter carrying the credential.
+	Name string `toml:"name"`
+	// Env is the environment variable the credential is read from.
+	Env string `toml:"env"`
+	// TokenCommand is a shell command printing the credential, run when
+	// the environment variable is not set.
+	TokenCommand string `toml:"token_command"`
+}
+
+// PromptConfig overrides or adds one prompt.
+type PromptConfig struct {
+	// Tool is the tool the prompt asks the model to call. A prompt the
+	// spec does not produce is added with the arguments of this tool,
+	// which defaults to the tool named like the prompt.
+	Tool        string `toml:"tool"`
+	Description string `toml:"description"`
+	// Template is the prompt message, with {name} placeholders for the
+	// arguments and {tool} for the tool name.
+	Template string `toml:"template"`
+}
+
+// CompletionConfig looks up the values of a prompt argument or resource
+// template variable.
+type CompletionConfig struct {
+	Tool string `toml:"tool"`
+	// Arguments are passed to the tool. In strings, {value} stands for the
+	// text typed so far and {name} for arguments already filled in.
+	Arguments map[string]any `toml:"arguments"`
+	// Items is the dotted path of the list in the response, empty when the
+	// response is the list.
+	Items string `toml:"items"`
+	// Field is the dotted path of the value in each item, empty when the
+	// items are the values.
+	Field string `toml:"field"`
+}
+
+// ToolConfig holds the overrides for one tool.
+type ToolConfig struct {
+	Annotations Annotations `toml:"annotations"`
+	// Timeout overrides the request timeout of the tool.
+	Timeout time.Duration `toml:"timeout"`
+	// RateLimit applies to the requests of the tool, on top of the limit
+	// of its upstream.
+	RateLimit *RateLimit `toml:"rate_limit"`
+	// Confirm sets whether the user approves each request of the tool,
+	// which defaults to its destructive hint.
+	Confirm *bool `toml:"confirm"`
+}
+
+// Annotations overrides MCP tool annotations. Unset fields keep the value
+// derived from the spec.
+type Annotations struct {
+	Title           string `toml:"title"`
+	ReadOnlyHint    *bool  `toml:"readOnlyHint"`
+	DestructiveHint *bool  `toml:"destructiveHint"`
+	IdempotentHint  *bool  `toml:"idempotentHint"`
+	OpenWorldHint   *bool  `toml:"openWorldHint"`
+}
+
+// Load reads the config file at path. An empty path yields the zero Config.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %v", err)
+	}
+	if err := toml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+	}
+	if err := cfg.Lint.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	if cfg.MaxBinarySize < 0 {
+		return nil, fmt.Errorf("invalid config %s: max_binary_size must not be negative", path)
+	}
+	if cfg.MaxResponseSize < 0 {
+		return nil, fmt.Errorf("invalid config %s: max_response_size must not be negative", path)
+	}
+	if cfg.Timeout < 0 || cfg.RetryBackoff < 0 || (cfg.Retries != nil && *cfg.Retries < 0) {
+		return nil, fmt.Errorf("invalid config %s: timeout, retries and retry_backoff must not be negative", path)
+	}
+	if err := cfg.RateLimit.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: rate_limit: %v", path, err)
+	}
+	for name, tool := range cfg.Tools {
+		if tool.Timeout < 0 {
+			return nil, fmt.Errorf("invalid config %s: timeout of tool %s must not be negative", path, name)
+		}
+		if err := tool.RateLimit.validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: rate_limit of tool %s: %v", path, name, err)
+		}
+	}
+	for _, pattern := range cfg.AllowedHosts {
+		if !core.ValidHostPattern(pattern) {
+			return nil, fmt.Errorf("invalid config %s: allowed_hosts: %q is not a host name such as api.example.com or *.example.com", path, pattern)
+		}
+	}
+	if cfg.TokenBudget < 0 {
+		return nil, fmt.Errorf("invalid config %s: token_budget must not be negative", path)
+	}
+	if cfg.ToolMode != "" && !core.ValidToolMode(cfg.ToolMode) {
+		return nil, fmt.Errorf("invalid config %s: tool_mode must be %s or %s", path, core.ToolModeStatic, core.ToolModeDynamic)
+	}
+	for i, src := range cfg.Sources {
+		if src.Spec == "" {
+			return nil, fmt.Errorf("invalid config %s: source %d has no spec", path, i+1)
+		}
+		if err := src.RateLimit.validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: rate_limit of source %d: %v", path, i+1, err)
+		}
+	}
+	return cfg, nil
+}
+
+// Apply overlays the server settings and per-tool overrides onto data.
+// Overrides for tools the spec does not produce are reported as errors so
+// typos do not go unnoticed. Settings left out for some tools are returned
+// as warnings for the caller to show.
+func (c *Config) Apply(data *core.TemplateData) (warnings []string, err error) {
+	if c.MaxBinarySize > 0 {
+		data.MaxBinarySize = c.MaxBinarySize
+	}
+	if c.MaxResponseSize > 0 {
+		data.MaxResponseSize = c.MaxResponseSize
+	}
+	if c.Timeout > 0 {
+		data.Timeout = c.Timeout.Seconds()
+	}
+	if c.Retries != nil {
+		data.Retries = *c.Retries
+	}
+	if c.RetryBackoff > 0 {
+		data.RetryBackoff = c.RetryBackoff.Seconds()
+	}
+	for i := range data.Upstreams {
+		data.Upstreams[i].RateLimit = data.Upstreams[i].RateLimit.Merge(c.RateLimit.Limit())
+	}
+	if len(c.AllowedHosts) > 0 {
+		if err := checkAllowedHosts(c.AllowedHosts, data.Upstreams); err != nil {
+			return nil, err
+		}
+		data.AllowedHosts = c.AllowedHosts
+	}
+	known := make(map[string]bool, len(data.Tools))
+	for i := range data.Tools {
+		tool := &data.Tools[i]
+		known[tool.Name] = true
+		override, ok := c.Tools[tool.Name]
+		if !ok {
+			continue
+		}
+		override.Annotations.apply(&tool.Annotations)
+		if override.Confirm != nil {
+			confirm := *override.Confirm
+			tool.Confirm = &confirm
+		}
+		if override.Timeout > 0 {
+			tool.Timeout = override.Timeout.Seconds()
+		}
+		tool.RateLimit = override.RateLimit.Limit().Merge(tool.RateLimit)
+	}
+	for name := range c.Tools {
+		if !known[name] {
+			return nil, fmt.Errorf("config overrides unknown tool %q", name)
+		}
+	}
+	if c.FieldsArgument {
+		for i := range data.Tools {
+			if err := addFieldsArgument(&data.Tools[i]); err != nil {
+				warnings = append(warnings, err.Error())
+			}
+		}
+	}
+	if err := c.applyPrompts(data); err != nil {
+		return nil, err
+	}
+	if err := c.applyCompletions(data); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+func (c *Config) applyPrompts(data *core.TemplateData) error {
+	if c.AutoPrompts != nil && !*c.AutoPrompts {
+		var kept []core.Prompt
+		for _, prompt := range data.Prompts {
+			if !prompt.Auto {
+				kept = append(kept, prompt)
+			}
+		}
+		data.Prompts = kept
+	}
+	names := make([]string, 0, len(c.Prompts))
+	for name := range c.Prompts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		override := c.Prompts[name]
+		prompt := findPrompt(data, name)
+		if prompt == nil {
+			toolName := override.Tool
+			if toolName == "" {
+				toolName = name
+			}
+			tool := findTool(data, toolName)
+			if tool == nil {
+				return fmt.Errorf("config prompt %q calls unknown tool %q, set its tool", name, toolName)
+			}
+			data.Prompts = append(data.Prompts, core.Prompt{
+				Name:        name,
+				Description: tool.Description,
+				Arguments:   promptArguments(tool.Arguments),
+				Tool:        tool.Name,
+			})
+			prompt = &data.Prompts[len(data.Prompts)-1]
+		} else if override.Tool != "" && override.Tool != prompt.Tool {
+			return fmt.Errorf("config prompt %q calls tool %q, but the spec binds it to %q", name, override.Tool, prompt.Tool)
+		}
+		if override.Description != "" {
+			prompt.Description = override.Description
+		}
+		if override.Template != "" {
+			prompt.Template = override.Template
+		}
+		if err := prompt.CheckTemplate(); err != nil {
+			return fmt.Errorf("config %v", err)
+		}
+	}
+	return nil
+}
+
+func (c *Config) applyCompletions(data *core.TemplateData) error {
+	arguments := make(map[string]bool)
+	for _, prompt := range data.Prompts {
+		for _, arg := range prompt.Arguments {
+			arguments[arg.Name] = true
+		}
+	}
+	// template variables are named like the path parameter
+	for _, resource := range data.ResourceTemplates() {
+		for _, arg := range resource.Arguments {
+			arguments[arg.WireName] = true
+		}
+	}
+	names := make([]string, 0, len(c.Completions))
+	for name := range c.Completions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		completion := c.Completions[name]
+		if !arguments[name] {
+			return fmt.Errorf("config completes %q, which is not a prompt argument or resource template variable", name)
+		}
+		tool := findTool(data, completion.Tool)
+		if tool == nil {
+			return fmt.Errorf("config completes %q with unknown tool %q", name, completion.Tool)
+		}
+		for arg := range completion.Arguments {
+			known := false
+			for _, toolArg := range tool.Arguments {
+				known = known || toolArg.Name == arg
+			}
+			if !known {
+				return fmt.Errorf("config completes %q passing %q, which is not an argument of %s", name, arg, tool.Name)
+			}
+		}
+		data.Lookups = append(data.Lookups, core.Lookup{
+			Argument:  name,
+			Tool:      tool.Name,
+			Arguments: completion.Arguments,
+			Items:     completion.Items,
+			Field:     completion.Field,
+		})
+	}
+	return nil
+}
+
+// checkAllowedHosts reports base URLs whose host the allowlist leaves out,
+// as the generated server would refuse every request to them. Base URLs
+// with server variables are not checked.
+func checkAllowedHosts(patterns []string, upstreams []core.Upstream) error {
+	for _, upstream := range upstreams {
+		for _, endpoint := range upstream.Endpoints {
+			u, err := url.Parse(endpoint)
+			if err != nil || u.Hostname() == "" || strings.Contains(endpoint, "{") {
+				continue
+			}
+			if !core.HostAllowed(patterns, u.Hostname()) {
+				return fmt.Errorf("base URL %s of %s is not in allowed_hosts", endpoint, upstream.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// fieldsArgument is the name of the argument added by FieldsArgument.
+const fieldsArgument = "fields"
+
+// addFieldsArgument lets the model select parts of the JSON response of
+// tool. A selection leaves out properties, so the output schema no longer
+// requires any. A tool already taking a fields argument is left as it is and
+// reported in the returned error.
+func addFieldsArgument(tool *core.Tool) error {
+	if !slices.ContainsFunc(tool.ResponseTypes, shared.IsJSON) {
+		return nil
+	}
+	for _, arg := range tool.Arguments {
+		if arg.Name == fieldsArgument {
+			return fmt.Errorf("tool %s already takes an argument named %s, not adding the response field selection", tool.Name, fieldsArgument)
+		}
+	}
+	arg := core.Argument{
+		Name:        fieldsArgument,
+		Description: "Dotted paths of the response fields to return, such as id or tags.name, applied to every item when the response is a list. Returns all fields when omitted.",
+		Type:        "array",
+		In:          core.InFields,
+		WireName:    fieldsArgument,
+		Schema:      map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+	}
+	tool.Arguments = append(tool.Arguments, arg)
+	if properties, ok := tool.InputSchema["properties"].(map[string]any); ok {
+		properties[arg.Name] = map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": arg.Description}
+	}
+	if tool.OutputSchema != nil {
+		tool.OutputSchema = withoutRequired(tool.OutputSchema)
+	}
+	return nil
+}
+
+// withoutRequired copies a JSON Schema, dropping the required lists of it
+// and of every subschema.
+func withoutRequired(schema map[string]any) map[string]any {
+	out := make(map[string]any, len(schema))
+	for key, value := range schema {
+		switch key {
+		case "required":
+			continue
+		case "properties", "$defs":
+			if schemas, ok := value.(map[string]any); ok {
+				copied := make(map[string]any, len(schemas))
+				for name, sub := range schemas {
+					copied[name] = subschemaWithoutRequired(sub)
+				}
+				value = copied
+			}
+		case "allOf", "anyOf", "oneOf":
+			if list, ok := value.([]any); ok {
+				copied := make([]any, len(list))
+				for i, sub := range list {
+					copied[i] = subschemaWithoutRequired(sub)
+				}
+				value = copied
+			}
+		case "items", "additionalProperties", "not":
+			value = subschemaWithoutRequired(value)
+		}
+		out[key] = value
+	}
+	return out
+}
+
+func subschemaWithoutRequired(schema any) any {
+	if m, ok := schema.(map[string]any); ok {
+		return withoutRequired(m)
+	}
+	return schema
+}
+
+func findPrompt(data *core.TemplateData, name string) *core.Prompt {
+	for i := range data.Prompts {
+		if data.Prompts[i].Name == name {
+			return &data.Prompts[i]
+		}
+	}
+	return nil
+}
+
+func findTool(data *core.TemplateData, name string) *core.Tool {
+	for i := range data.Tools {
+		if data.Tools[i].Name == name {
+			return &data.Tools[i]
+		}
+	}
+	return nil
+}
+
+// promptArguments keeps the arguments a user can type in, leaving out
+// file contents and the response field selection.
+func promptArguments(arguments []core.Argument) []core.Argument {
+	var kept []core.Argument
+	for _, arg := range arguments {
+		if !arg.Binary && arg.In != core.InFields {
+			kept = append(kept, arg)
+		}
+	}
+	return kept
+}
+
+func (a Annotations) apply(annotations *core.ToolAnnotations) {
+	if a.Title != "" {
+		annotations.Title = a.Title
+	}
+	if a.ReadOnlyHint != nil {
+		annotations.ReadOnlyHint = a.ReadOnlyHint
+	}
+	if a.DestructiveHint != nil {
+		annotations.DestructiveHint = a.DestructiveHint
+	}
+	if a.IdempotentHint != nil {
+		annotations.IdempotentHint = a.IdempotentHint
+	}
+	if a.OpenWorldHint != nil {
+		annotations.OpenWorldHint = a.OpenWorldHint
+	}
+}
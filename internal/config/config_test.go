@@ -0,0 +1,367 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ai-create-mcp.toml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, &Config{}, cfg)
+
+	cfg, err = Load(writeConfig(t, `
+package_manager = "poetry"
+offline = true
+max_binary_size = 1048576
+max_response_size = 65536
+fields_argument = true
+allowed_hosts = ["api.example.com", "*.example.org"]
+tool_mode = "dynamic"
+token_budget = 20000
+timeout = "45s"
+retries = 5
+retry_backoff = "250ms"
+auto_prompts = false
+
+[rate_limit]
+rate = "10/s"
+burst = 20
+concurrency = 4
+
+[prompts.adopt_pet]
+tool = "get_pet_by_petId"
+template = "Look up pet {petId}"
+
+[completions.petId]
+tool = "find_pets_by_status"
+arguments = { status = "available" }
+field = "id"
+
+[lint.rules]
+missing-description = "error"
+
+[tools.add_pet]
+confirm = true
+
+[tools.delete_pet_by_petId.annotations]
+title = "Remove a pet"
+destructiveHint = false
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "poetry", cfg.PackageManager)
+	assert.True(t, cfg.Offline)
+	assert.Equal(t, int64(1<<20), cfg.MaxBinarySize)
+	assert.Equal(t, int64(64<<10), cfg.MaxResponseSize)
+	assert.True(t, cfg.FieldsArgument)
+	assert.Equal(t, []string{"api.example.com", "*.example.org"}, cfg.AllowedHosts)
+	assert.Equal(t, core.ToolModeDynamic, cfg.ToolMode)
+	assert.Equal(t, 20000, cfg.TokenBudget)
+	assert.Equal(t, 45*time.Second, cfg.Timeout)
+	require.NotNil(t, cfg.Retries)
+	assert.Equal(t, 5, *cfg.Retries)
+	assert.Equal(t, 250*time.Millisecond, cfg.RetryBackoff)
+	assert.Equal(t, core.RateLimit{Rate: 10, Burst: 20, Concurrency: 4}, cfg.RateLimit.Limit())
+	require.NotNil(t, cfg.AutoPrompts)
+	assert.False(t, *cfg.AutoPrompts)
+	assert.Equal(t, PromptConfig{Tool: "get_pet_by_petId", Template: "Look up pet {petId}"}, cfg.Prompts["adopt_pet"])
+	assert.Equal(t, CompletionConfig{Tool: "find_pets_by_status", Arguments: map[string]any{"status": "available"}, Field: "id"}, cfg.Completions["petId"])
+	assert.Equal(t, "error", cfg.Lint.Rules["missing-description"])
+	annotations := cfg.Tools["delete_pet_by_petId"].Annotations
+	assert.Equal(t, "Remove a pet", annotations.Title)
+	require.NotNil(t, annotations.DestructiveHint)
+	assert.False(t, *annotations.DestructiveHint)
+	assert.Nil(t, annotations.ReadOnlyHint)
+	require.NotNil(t, cfg.Tools["add_pet"].Confirm)
+	assert.True(t, *cfg.Tools["add_pet"].Confirm)
+
+	_, err = Load(writeConfig(t, "[lint.rules]\nunknown = \"error\"\n"))
+	require.Error(t, err)
+
+	_, err = Load(writeConfig(t, "tool_mode = \"lazy\"\n"))
+	require.ErrorContains(t, err, "tool_mode")
+
+	_, err = Load(writeConfig(t, "[rate_limit]\nrate = \"fast\"\n"))
+	require.ErrorContains(t, err, "rate_limit: invalid rate")
+
+	_, err = Load(writeConfig(t, "[tools.get_pet.rate_limit]\nburst = 3\n"))
+	require.ErrorContains(t, err, "rate_limit of tool get_pet: burst needs a rate")
+
+	_, err = Load(writeConfig(t, "allowed_hosts = [\"https://api.example.com\"]\n"))
+	require.ErrorContains(t, err, "is not a host name")
+
+	_, err = Load(writeConfig(t, "max_response_size = -1\n"))
+	require.ErrorContains(t, err, "max_response_size must not be negative")
+
+	_, err = Load(writeConfig(t, "retries = -1\n"))
+	require.ErrorContains(t, err, "must not be negative")
+
+	_, err = Load(writeConfig(t, "[tools.get_pet]\ntimeout = \"-1s\"\n"))
+	require.ErrorContains(t, err, "timeout of tool get_pet")
+}
+
+func TestLoadSources(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+[[sources]]
+spec = "pets.yaml"
+
+[[sources]]
+name = "orders"
+spec = "https://orders.internal/openapi.json"
+prefix = "orders"
+base_url = "https://orders.internal"
+overlays = ["orders-overlay.yaml"]
+
+[sources.auth]
+type = "header"
+name = "X-API-Key"
+env = "ORDERS_KEY"
+token_command = "op read op://ci/orders/key"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []SourceConfig{
+		{Spec: "pets.yaml"},
+		{
+			Name:     "orders",
+			Spec:     "https://orders.internal/openapi.json",
+			Prefix:   "orders",
+			BaseURL:  "https://orders.internal",
+			Overlays: []string{"orders-overlay.yaml"},
+			Auth:     &Auth{Type: "header", Name: "X-API-Key", Env: "ORDERS_KEY", TokenCommand: "op read op://ci/orders/key"},
+		},
+	}, cfg.Sources)
+
+	_, err = Load(writeConfig(t, "[[sources]]\nname = \"pets\"\n"))
+	assert.ErrorContains(t, err, "source 1 has no spec")
+}
+
+func TestApply(t *testing.T) {
+	yes, no := true, false
+	data := &core.TemplateData{MaxBinarySize: core.DefaultMaxBinarySize, Timeout: core.DefaultTimeout, Retries: core.DefaultRetries, RetryBackoff: core.DefaultRetryBackoff, Tools: []core.Tool{
+		{Name: "delete_pet", Annotations: core.ToolAnnotations{Title: "Deletes a pet", DestructiveHint: &yes, OpenWorldHint: &yes}},
+		{Name: "get_pet", Annotations: core.ToolAnnotations{Title: "Find pet"}},
+	}}
+	data.Upstreams = []core.Upstream{{Name: "api", RateLimit: core.RateLimit{Concurrency: 8}}}
+	data.Tools[1].RateLimit = core.RateLimit{Rate: 1, Burst: 2}
+	retries := 0
+	cfg := &Config{MaxBinarySize: 1024, Timeout: 10 * time.Second, Retries: &retries, RateLimit: &RateLimit{Rate: "5/s", Concurrency: 2}, Tools: map[string]ToolConfig{
+		"delete_pet": {Annotations: Annotations{DestructiveHint: &no, IdempotentHint: &yes}, Timeout: 2 * time.Minute},
+		"get_pet":    {RateLimit: &RateLimit{Rate: "30/m"}},
+	}}
+
+	_, err := cfg.Apply(data)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1024), data.MaxBinarySize)
+	assert.Equal(t, 10.0, data.Timeout)
+	assert.Equal(t, 0, data.Retries)
+	assert.Equal(t, core.DefaultRetryBackoff, data.RetryBackoff)
+	assert.Equal(t, 120.0, data.Tools[0].Timeout)
+	assert.Equal(t, 0.0, data.Tools[1].Timeout)
+	assert.True(t, data.Tools[0].Retryable())
+	assert.False(t, data.Tools[1].Retryable())
+	// the config rate limit is a default for the spec's, a tool's overrides it
+	assert.Equal(t, core.RateLimit{Rate: 5, Concurrency: 8}, data.Upstreams[0].RateLimit)
+	assert.Equal(t, core.RateLimit{}, data.Tools[0].RateLimit)
+	assert.Equal(t, core.RateLimit{Rate: 0.5, Burst: 2}, data.Tools[1].RateLimit)
+	assert.Equal(t, core.ToolAnnotations{Title: "Deletes a pet", DestructiveHint: &no, IdempotentHint: &yes, OpenWorldHint: &yes}, data.Tools[0].Annotations)
+	assert.Equal(t, core.ToolAnnotations{Title: "Find pet"}, data.Tools[1].Annotations)
+
+	cfg.Tools["typo"] = ToolConfig{}
+	_, err = cfg.Apply(data)
+	require.Error(t, err)
+}
+
+func TestApplyConfirm(t *testing.T) {
+	yes, no := true, false
+	destructive := core.ToolAnnotations{DestructiveHint: &yes}
+	data := &core.TemplateData{Tools: []core.Tool{
+		// x-mcp-confirm: true and false in the spec
+		{Name: "post_payment", Confirm: &yes},
+		{Name: "delete_cart", Annotations: destructive, Confirm: &no},
+		{Name: "delete_pet", Annotations: destructive},
+		{Name: "delete_order", Annotations: destructive},
+		{Name: "get_pet"},
+	}}
+	cfg := &Config{Tools: map[string]ToolConfig{
+		"post_payment": {Annotations: Annotations{DestructiveHint: &no}},
+		"delete_cart":  {Annotations: Annotations{DestructiveHint: &yes}},
+		"delete_pet":   {Annotations: Annotations{DestructiveHint: &no}},
+		"delete_order": {Confirm: &no},
+		"get_pet":      {Confirm: &yes},
+	}}
+
+	_, err := cfg.Apply(data)
+	require.NoError(t, err)
+	confirm := make(map[string]bool)
+	for _, tool := range data.Tools {
+		confirm[tool.Name] = tool.NeedsConfirmation()
+	}
+	// a destructive hint only decides for tools without an explicit setting
+	assert.Equal(t, map[string]bool{
+		"post_payment": true,
+		"delete_cart":  false,
+		"delete_pet":   false,
+		"delete_order": false,
+		"get_pet":      true,
+	}, confirm)
+}
+
+func TestApplyAllowedHosts(t *testing.T) {
+	newData := func(endpoints ...string) *core.TemplateData {
+		return &core.TemplateData{Upstreams: []core.Upstream{{Name: "api", Endpoints: endpoints}}}
+	}
+	cfg := &Config{AllowedHosts: []string{"api.example.com", "*.example.org"}}
+
+	data := newData("https://api.example.com/v1", "https://eu.example.org", "https://{region}.example.net", "/relative")
+	_, err := cfg.Apply(data)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.AllowedHosts, data.AllowedHosts)
+
+	// a wildcard only matches subdomains
+	_, err = cfg.Apply(newData("https://example.org"))
+	assert.ErrorContains(t, err, "base URL https://example.org of api is not in allowed_hosts")
+	_, err = cfg.Apply(newData("https://API.EXAMPLE.COM:8443"))
+	assert.NoError(t, err)
+}
+
+func TestApplyFieldsArgument(t *testing.T) {
+	data := &core.TemplateData{MaxResponseSize: core.DefaultMaxResponseSize, Tools: []core.Tool{
+		{
+			Name:          "get_pet",
+			ResponseTypes: []string{"application/json"},
+			InputSchema:   map[string]any{"type": "object", "properties": map[string]any{}},
+			OutputSchema: map[string]any{
+				"type":     "object",
+				"required": []any{"id", "tags"},
+				"properties": map[string]any{
+					"id":   map[string]any{"type": "integer"},
+					"tags": map[string]any{"type": "array", "items": map[string]any{"type": "object", "required": []any{"name"}}},
+				},
+			},
+		},
+		{Name: "get_photo", ResponseTypes: []string{"image/png"}},
+		{Name: "search", ResponseTypes: []string{"application/hal+json"}, Arguments: []core.Argument{{Name: "fields", In: "query"}}},
+	}}
+	cfg := &Config{MaxResponseSize: 2048, FieldsArgument: true}
+
+	warnings, err := cfg.Apply(data)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2048), data.MaxResponseSize)
+	get := data.Tools[0]
+	require.Len(t, get.Arguments, 1)
+	assert.Equal(t, core.InFields, get.Arguments[0].In)
+	assert.Contains(t, get.InputSchema["properties"], "fields")
+	// a selection may leave out any property
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id":   map[string]any{"type": "integer"},
+			"tags": map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+		},
+	}, get.OutputSchema)
+	assert.Empty(t, data.Tools[1].Arguments)
+	// the spec's own fields argument is kept
+	assert.Equal(t, []core.Argument{{Name: "fields", In: "query"}}, data.Tools[2].Arguments)
+	assert.Equal(t, []string{"tool search already takes an argument named fields, not adding the response field selection"}, warnings)
+}
+
+func TestApplyPrompts(t *testing.T) {
+	petID := core.Argument{Name: "petId", Required: true}
+	newData := func() *core.TemplateData {
+		return &core.TemplateData{
+			Tools: []core.Tool{
+				{Name: "get_pet", Description: "Find pet", Arguments: []core.Argument{petID}},
+				{Name: "post_photo", Description: "Upload a photo", Arguments: []core.Argument{petID, {Name: "file", Binary: true}}},
+			},
+			Prompts: []core.Prompt{
+				{Name: "get_pet", Description: "Find pet", Arguments: []core.Argument{petID}, Tool: "get_pet", Auto: true},
+				{Name: "list_pets", Tool: "get_pets"},
+			},
+		}
+	}
+	no := false
+
+	data := newData()
+	cfg := &Config{AutoPrompts: &no, Prompts: map[string]PromptConfig{
+		"get_pet":     {Template: "Look up pet {petId} and summarize it."},
+		"share_photo": {Tool: "post_photo", Template: "Upload a photo of pet {petId} with {tool}."},
+	}}
+	_, err := cfg.Apply(data)
+	require.NoError(t, err)
+	assert.Equal(t, []core.Prompt{
+		{Name: "list_pets", Tool: "get_pets"},
+		// dropped as automatic, then added back from the tool of the same name
+		{Name: "get_pet", Description: "Find pet", Arguments: []core.Argument{petID}, Tool: "get_pet", Template: "Look up pet {petId} and summarize it."},
+		{Name: "share_photo", Description: "Upload a photo", Arguments: []core.Argument{petID}, Tool: "post_photo", Template: "Upload a photo of pet {petId} with {tool}."},
+	}, data.Prompts)
+
+	tests := []struct {
+		prompts map[string]PromptConfig
+		want    string
+	}{
+		{map[string]PromptConfig{"typo": {}}, `unknown tool "typo"`},
+		{map[string]PromptConfig{"get_pet": {Tool: "post_photo"}}, `but the spec binds it to "get_pet"`},
+		{map[string]PromptConfig{"get_pet": {Template: "Find {id}"}}, "template uses {id}"},
+	}
+	for _, tt := range tests {
+		_, err := (&Config{Prompts: tt.prompts}).Apply(newData())
+		assert.ErrorContains(t, err, tt.want)
+	}
+}
+
+func TestApplyCompletions(t *testing.T) {
+	newData := func() *core.TemplateData {
+		return &core.TemplateData{
+			Tools: []core.Tool{
+				{Name: "get_pet", Arguments: []core.Argument{{Name: "petId", Required: true}}},
+				{Name: "find_pets", Arguments: []core.Argument{{Name: "name"}}},
+			},
+			Prompts: []core.Prompt{
+				{Name: "get_pet", Arguments: []core.Argument{{Name: "petId", Required: true}}, Tool: "get_pet"},
+			},
+			Resources: []core.Resource{
+				{Name: "order", URI: "ai-create-mcp://internal/orders_{order-id}", Arguments: []core.Argument{{Name: "order_id", WireName: "order-id", In: "path"}}},
+			},
+		}
+	}
+
+	data := newData()
+	cfg := &Config{Completions: map[string]CompletionConfig{
+		"petId": {Tool: "find_pets", Arguments: map[string]any{"name": "{value}"}, Items: "data", Field: "id"},
+		// template variables are completed under the name in the URI
+		"order-id": {Tool: "find_pets"},
+	}}
+	_, err := cfg.Apply(data)
+	require.NoError(t, err)
+	assert.Equal(t, []core.Lookup{
+		{Argument: "order-id", Tool: "find_pets"},
+		{Argument: "petId", Tool: "find_pets", Arguments: map[string]any{"name": "{value}"}, Items: "data", Field: "id"},
+	}, data.Lookups)
+
+	tests := []struct {
+		completions map[string]CompletionConfig
+		want        string
+	}{
+		{map[string]CompletionConfig{"name": {Tool: "find_pets"}}, `"name", which is not a prompt argument or resource template variable`},
+		{map[string]CompletionConfig{"order_id": {Tool: "find_pets"}}, `"order_id", which is not a prompt argument`},
+		{map[string]CompletionConfig{"petId": {Tool: "typo"}}, `unknown tool "typo"`},
+		{map[string]CompletionConfig{"petId": {Tool: "find_pets", Arguments: map[string]any{"tag": "x"}}}, `"tag", which is not an argument of find_pets`},
+	}
+	for _, tt := range tests {
+		_, err := (&Config{Completions: tt.completions}).Apply(newData())
+		assert.ErrorContains(t, err, tt.want)
+	}
+}
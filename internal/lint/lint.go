@@ -0,0 +1,299 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/shared"
+)
+
+// Severity ranks a finding. Off disables a rule.
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+	Info    Severity = "info"
+	Off     Severity = "off"
+)
+
+func (s Severity) valid() bool {
+	switch s {
+	case Error, Warning, Info, Off:
+		return true
+	}
+	return false
+}
+
+// Rule identifiers, usable as keys of Config.Rules.
+const (
+	MissingDescription          = "missing-description"
+	MissingParameterDescription = "missing-parameter-description"
+	DuplicateToolName           = "duplicate-tool-name"
+	InvalidToolName             = "invalid-tool-name"
+	ToolNameTooLong             = "tool-name-too-long"
+	TooManyArguments            = "too-many-arguments"
+	UnsupportedSchema           = "unsupported-schema"
+)
+
+// Rules maps every rule to its default severity.
+var Rules = map[string]Severity{
+	MissingDescription:          Warning,
+	MissingParameterDescription: Info,
+	DuplicateToolName:           Error,
+	InvalidToolName:             Error,
+	ToolNameTooLong:             Error,
+	TooManyArguments:            Warning,
+	UnsupportedSchema:           Warning,
+}
+
+const (
+	// DefaultMaxNameLength is the longest tool name MCP clients reliably accept.
+	DefaultMaxNameLength = 64
+	DefaultMaxArguments  = 20
+)
+
+// Config tunes the linter. Zero values fall back to the defaults.
+type Config struct {
+	// Rules overrides the severity of individual rules: error, warning, info or off.
+	Rules         map[string]string `toml:"rules"`
+	MaxNameLength int               `toml:"max_name_length"`
+	MaxArguments  int               `toml:"max_arguments"`
+}
+
+// Validate reports unknown rules and severities.
+func (c Config) Validate() error {
+	for rule, severity := range c.Rules {
+		if _, ok := Rules[rule]; !ok {
+			return fmt.Errorf("unknown lint rule %q", rule)
+		}
+		if !Severity(severity).valid() {
+			return fmt.Errorf("invalid severity %q for lint rule %s", severity, rule)
+		}
+	}
+	return nil
+}
+
+func (c Config) severity(rule string) Severity {
+	if s, ok := c.Rules[rule]; ok {
+		return Severity(s)
+	}
+	return Rules[rule]
+}
+
+// Finding is a single problem reported by the linter.
+type Finding struct {
+	Rule      string   `json:"rule"`
+	Severity  Severity `json:"severity"`
+	Tool      string   `json:"tool,omitempty"`
+	Operation string   `json:"operation,omitempty"` // "METHOD /path"
+	Message   string   `json:"message"`
+}
+
+func (f Finding) String() string {
+	location := f.Operation
+	if f.Tool != "" {
+		location = fmt.Sprintf("%s (%s)", f.Operation, f.Tool)
+	}
+	return fmt.Sprintf("%-7s %-29s %s: %s", f.Severity, f.Rule, location, f.Message)
+}
+
+var validToolName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Run checks the tools converted from doc for problems that make them hard
+// for an agent to use. data must be the conversion result of doc.
+func Run(doc *openapi3.T, data *core.TemplateData, cfg Config) []Finding {
+	if cfg.MaxNameLength <= 0 {
+		cfg.MaxNameLength = DefaultMaxNameLength
+	}
+	if cfg.MaxArguments <= 0 {
+		cfg.MaxArguments = DefaultMaxArguments
+	}
+
+	l := &linter{cfg: cfg}
+	seen := make(map[string]int)
+	for _, tool := range data.Tools {
+		seen[tool.Name]++
+	}
+
+	for _, tool := range data.Tools {
+		op := tool.Method + " " + tool.Path
+		if seen[tool.Name] > 1 {
+			l.report(DuplicateToolName, tool.Name, op, "tool name is shared by %d operations; only one of them can be called", seen[tool.Name])
+		}
+		if !validToolName.MatchString(tool.Name) {
+			l.report(InvalidToolName, tool.Name, op, "tool name contains characters outside [a-zA-Z0-9_-]")
+		}
+		if len(tool.Name) > cfg.MaxNameLength {
+			l.report(ToolNameTooLong, tool.Name, op, "tool name is %d characters long, the limit is %d", len(tool.Name), cfg.MaxNameLength)
+		}
+		if len(tool.Arguments) > cfg.MaxArguments {
+			l.report(TooManyArguments, tool.Name, op, "tool takes %d arguments, more than %d", len(tool.Arguments), cfg.MaxArguments)
+		}
+
+		operation := findOperation(doc, tool.Method, tool.Path)
+		if operation == nil {
+			continue
+		}
+		if !documented(operation.Summary, operation.Description, operation.Extensions) {
+			l.report(MissingDescription, tool.Name, op, "operation has no summary or description; the tool falls back to a generic one")
+		}
+		for _, param := range operation.Parameters {
+			if param.Value == nil || excluded(param.Value.Extensions) {
+				continue
+			}
+			if !documented(param.Value.Description, "", param.Value.Extensions) {
+				l.report(MissingParameterDescription, tool.Name, op, "parameter %s has no description", param.Value.Name)
+			}
+			l.checkSchema(tool.Name, op, "parameter "+param.Value.Name, param.Value.Schema)
+		}
+		l.checkRequestBody(tool.Name, op, operation.RequestBody)
+	}
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		return rank(l.findings[i].Severity) < rank(l.findings[j].Severity)
+	})
+	return l.findings
+}
+
+type linter struct {
+	cfg      Config
+	findings []Finding
+}
+
+func (l *linter) report(rule, tool, operation, format string, args ...interface{}) {
+	severity := l.cfg.severity(rule)
+	if severity == Off {
+		return
+	}
+	l.findings = append(l.findings, Finding{
+		Rule:      rule,
+		Severity:  severity,
+		Tool:      tool,
+		Operation: operation,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) checkRequestBody(tool, op string, body *openapi3.RequestBodyRef) {
+	if body == nil || body.Value == nil {
+		return
+	}
+	// only the content type the generated server sends is converted
+	contentType := shared.RequestContentType(body.Value.Content)
+	if contentType == "" {
+		return
+	}
+	content := body.Value.Content[contentType]
+	if content.Schema == nil || content.Schema.Value == nil {
+		return
+	}
+	// properties first, so findings name the property they are in
+	seen := make(map[*openapi3.Schema]bool)
+	defer l.walkSchema(tool, op, "request body", content.Schema, seen)
+	schema := content.Schema.Value
+	for _, name := range sortedKeys(schema.Properties) {
+		prop := schema.Properties[name]
+		if prop.Value == nil {
+			continue
+		}
+		if excluded(prop.Value.Extensions) {
+			seen[prop.Value] = true
+			continue
+		}
+		if !documented(prop.Value.Description, "", prop.Value.Extensions) {
+			l.report(MissingParameterDescription, tool, op, "body property %s has no description", name)
+		}
+		l.walkSchema(tool, op, "body property "+name, prop, seen)
+	}
+}
+
+// checkSchema reports schema constructs the converter cannot express, such
+// as discriminators it cannot pin on the variants they select.
+func (l *linter) checkSchema(tool, op, what string, ref *openapi3.SchemaRef) {
+	l.walkSchema(tool, op, what, ref, make(map[*openapi3.Schema]bool))
+}
+
+func (l *linter) walkSchema(tool, op, what string, ref *openapi3.SchemaRef, seen map[*openapi3.Schema]bool) {
+	if ref == nil || ref.Value == nil || seen[ref.Value] {
+		return
+	}
+	s := ref.Value
+	seen[s] = true
+	if d := s.Discriminator; d != nil && (len(s.OneOf) > 0 || len(s.AnyOf) > 0) {
+		variants := append(append(openapi3.SchemaRefs{}, s.OneOf...), s.AnyOf...)
+		refs := make(map[string]bool, len(variants))
+		for i, variant := range variants {
+			if variant.Ref == "" {
+				l.report(UnsupportedSchema, tool, op, "%s: discriminator %s cannot select inline variant %d, use a $ref", what, d.PropertyName, i+1)
+				continue
+			}
+			refs[variant.Ref] = true
+			refs[variant.Ref[strings.LastIndex(variant.Ref, "/")+1:]] = true
+		}
+		for _, value := range sortedKeys(d.Mapping) {
+			if target := d.Mapping[value]; !refs[target] {
+				l.report(UnsupportedSchema, tool, op, "%s: discriminator %s maps %q to %s, which is not one of the variants", what, d.PropertyName, value, target)
+			}
+		}
+	}
+	for _, nested := range [][]*openapi3.SchemaRef{s.AllOf, s.OneOf, s.AnyOf, {s.Items, s.Not, s.AdditionalProperties.Schema}} {
+		for _, n := range nested {
+			l.walkSchema(tool, op, what, n, seen)
+		}
+	}
+	for _, name := range sortedKeys(s.Properties) {
+		l.walkSchema(tool, op, what, s.Properties[name], seen)
+	}
+}
+
+// documented reports whether any of the given descriptions, including an
+// x-mcp-description override, is non-empty.
+func documented(summary, description string, extensions map[string]any) bool {
+	override, _ := extensions["x-mcp-description"].(string)
+	for _, s := range []string{summary, description, override} {
+		if strings.TrimSpace(s) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func excluded(extensions map[string]any) bool {
+	exclude, _ := extensions["x-mcp-exclude"].(bool)
+	return exclude
+}
+
+func findOperation(doc *openapi3.T, method, path string) *openapi3.Operation {
+	if doc == nil || doc.Paths == nil {
+		return nil
+	}
+	item := doc.Paths.Value(path)
+	if item == nil {
+		return nil
+	}
+	return item.GetOperation(method)
+}
+
+func rank(s Severity) int {
+	switch s {
+	case Error:
+		return 0
+	case Warning:
+		return 1
+	}
+	return 2
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
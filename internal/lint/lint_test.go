@@ -0,0 +1,149 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/shared"
+)
+
+const spec = `
+openapi: 3.0.3
+info:
+  title: Lint API
+  version: 1.0.0
+servers:
+  - url: https://lint.test
+paths:
+  /orders:
+    get:
+      parameters:
+        - name: status
+          in: query
+          schema:
+            type: string
+    post:
+      summary: Create an order
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                item:
+                  description: Item to order
+                  oneOf:
+                    - type: string
+                    - type: integer
+                product:
+                  description: Product to order
+                  oneOf:
+                    - $ref: '#/components/schemas/Book'
+                    - type: object
+                      properties:
+                        kind: {type: string}
+                  discriminator:
+                    propertyName: kind
+                    mapping:
+                      film: '#/components/schemas/Film'
+  /orders/{id}:
+    get:
+      summary: Duplicate after name cleanup
+      parameters:
+        - name: id
+          in: path
+          required: true
+          description: Order id
+          schema:
+            type: string
+  /orders_{id}:
+    get:
+      summary: Fetch an order
+      parameters:
+        - name: id
+          in: path
+          required: true
+          description: Order id
+          schema:
+            type: string
+components:
+  schemas:
+    Book:
+      type: object
+      properties:
+        kind: {type: string}
+    Film:
+      type: object
+      properties:
+        kind: {type: string}
+`
+
+func run(t *testing.T, cfg Config) []Finding {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	data, err := shared.Convert(doc)
+	require.NoError(t, err)
+	return Run(doc, data, cfg)
+}
+
+func rulesOf(findings []Finding) map[string][]Finding {
+	byRule := make(map[string][]Finding)
+	for _, f := range findings {
+		byRule[f.Rule] = append(byRule[f.Rule], f)
+	}
+	return byRule
+}
+
+func TestRun(t *testing.T) {
+	findings := run(t, Config{})
+	byRule := rulesOf(findings)
+
+	require.Len(t, byRule[MissingDescription], 1)
+	assert.Equal(t, "GET /orders", byRule[MissingDescription][0].Operation)
+	assert.Equal(t, Warning, byRule[MissingDescription][0].Severity)
+
+	require.Len(t, byRule[MissingParameterDescription], 1)
+	assert.Contains(t, byRule[MissingParameterDescription][0].Message, "status")
+
+	require.Len(t, byRule[DuplicateToolName], 2)
+	assert.Equal(t, "get_orders_by_id", byRule[DuplicateToolName][0].Tool)
+
+	// plain unions are converted, only discriminator mistakes are reported
+	require.Len(t, byRule[UnsupportedSchema], 2)
+	assert.Contains(t, byRule[UnsupportedSchema][0].Message, "inline variant 2")
+	assert.Contains(t, byRule[UnsupportedSchema][1].Message, `maps "film"`)
+
+	assert.Empty(t, byRule[ToolNameTooLong])
+	assert.Equal(t, Error, findings[0].Severity, "errors sort first")
+}
+
+func TestRunConfig(t *testing.T) {
+	cfg := Config{
+		Rules:         map[string]string{DuplicateToolName: "off", MissingDescription: "error"},
+		MaxNameLength: 10,
+		MaxArguments:  0,
+	}
+	byRule := rulesOf(run(t, cfg))
+
+	assert.Empty(t, byRule[DuplicateToolName])
+	require.Len(t, byRule[MissingDescription], 1)
+	assert.Equal(t, Error, byRule[MissingDescription][0].Severity)
+	for _, f := range byRule[ToolNameTooLong] {
+		assert.Greater(t, len(f.Tool), 10)
+	}
+	assert.Len(t, byRule[ToolNameTooLong], 3)
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, Config{Rules: map[string]string{TooManyArguments: "info"}}.Validate())
+
+	err := Config{Rules: map[string]string{"no-such-rule": "error"}}.Validate()
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "no-such-rule"))
+
+	require.Error(t, Config{Rules: map[string]string{TooManyArguments: "fatal"}}.Validate())
+}
@@ -0,0 +1,142 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileName is the manifest written at the root of every generated project.
+const FileName = ".ai-create-mcp.json"
+
+// Manifest records what produced a generated project: the generator and
+// template revisions, the specs it was converted from, the options used and
+// a content hash of every generated file.
+type Manifest struct {
+	GeneratorVersion string            `json:"generatorVersion"`
+	TemplateVersion  string            `json:"templateVersion"`
+	Specs            []Spec            `json:"specs"`
+	Options          Options           `json:"options"`
+	Files            map[string]string `json:"files"` // slash separated path relative to the project -> sha256
+}
+
+// Spec identifies one API description the project was generated from.
+type Spec struct {
+	Type     string `json:"type"`
+	Location string `json:"location"`
+	SHA256   string `json:"sha256"`
+}
+
+// Options are the generation settings that influence the project contents.
+type Options struct {
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+	Version        string `json:"version"`
+	PackageManager string `json:"packageManager"`
+	Offline        bool   `json:"offline,omitempty"`
+	ToolMode       string `json:"toolMode,omitempty"`
+	// Config is the path of the config file as given, and ConfigSHA256 the
+	// hash of its content. Both are empty without a config file.
+	Config       string `json:"config,omitempty"`
+	ConfigSHA256 string `json:"configSha256,omitempty"`
+}
+
+// AddFile records the hash of a generated file. rel is relative to the
+// project root.
+func (m *Manifest) AddFile(rel string, content []byte) {
+	if m.Files == nil {
+		m.Files = make(map[string]string)
+	}
+	m.Files[filepath.ToSlash(rel)] = Hash(content)
+}
+
+// Write stores the manifest as FileName inside dir.
+func (m *Manifest) Write(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %v", err)
+	}
+	path := filepath.Join(dir, FileName)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest %s: %v", path, err)
+	}
+	return nil
+}
+
+// Read loads the manifest of the project in dir.
+func Read(dir string) (*Manifest, error) {
+	path := filepath.Join(dir, FileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %v", err)
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %v", path, err)
+	}
+	return m, nil
+}
+
+// Drift returns the recorded files in dir whose content no longer matches
+// the manifest, including files that were deleted, sorted by path.
+func (m *Manifest) Drift(dir string) ([]string, error) {
+	var changed []string
+	for rel, want := range m.Files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if os.IsNotExist(err) {
+			changed = append(changed, rel)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if Hash(data) != want {
+			changed = append(changed, rel)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// ChangedInputs returns the spec, overlay and config files whose content
+// no longer matches the manifest, including files that were deleted.
+// Relative paths are resolved against the working directory, as they were
+// when the project was generated. Specs loaded from a URL are not checked.
+func (m *Manifest) ChangedInputs() ([]string, error) {
+	inputs := make(map[string]string, len(m.Specs)+1)
+	for _, spec := range m.Specs {
+		if !strings.HasPrefix(spec.Location, "http://") && !strings.HasPrefix(spec.Location, "https://") {
+			inputs[spec.Location] = spec.SHA256
+		}
+	}
+	if m.Options.Config != "" {
+		inputs[m.Options.Config] = m.Options.ConfigSHA256
+	}
+	var changed []string
+	for path, want := range inputs {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			changed = append(changed, path)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if Hash(data) != want {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// Hash returns the hex encoded sha256 of data.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,80 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteReadDrift(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"README.md":            "# demo\n",
+		"src/demo/server.py":   "print('hi')\n",
+		"src/demo/__init__.py": "",
+	}
+	m := &Manifest{
+		GeneratorVersion: "v0.1.0",
+		TemplateVersion:  "1",
+		Specs:            []Spec{{Type: "oas31", Location: "openapi.yml", SHA256: Hash([]byte("spec"))}},
+		Options:          Options{Name: "demo", Version: "0.1.0", PackageManager: "uv"},
+	}
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		m.AddFile(rel, []byte(content))
+	}
+	require.NoError(t, m.Write(dir))
+
+	got, err := Read(dir)
+	require.NoError(t, err)
+	assert.Equal(t, m, got)
+
+	drift, err := got.Drift(dir)
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("edited\n"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "src", "demo", "server.py")))
+	drift, err = got.Drift(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"README.md", "src/demo/server.py"}, drift)
+}
+
+func TestReadMissing(t *testing.T) {
+	_, err := Read(t.TempDir())
+	require.Error(t, err)
+}
+
+func TestChangedInputs(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "openapi.yml")
+	overlay := filepath.Join(dir, "overlay.yml")
+	cfg := filepath.Join(dir, "config.toml")
+	for _, path := range []string{spec, overlay, cfg} {
+		require.NoError(t, os.WriteFile(path, []byte(path), 0644))
+	}
+	m := &Manifest{
+		Specs: []Spec{
+			{Type: "oas31", Location: spec, SHA256: Hash([]byte(spec))},
+			{Type: "overlay", Location: overlay, SHA256: Hash([]byte(overlay))},
+			// not fetched
+			{Type: "oas31", Location: "https://example.com/openapi.yml", SHA256: Hash([]byte("remote"))},
+		},
+		Options: Options{Config: cfg, ConfigSHA256: Hash([]byte(cfg))},
+	}
+
+	changed, err := m.ChangedInputs()
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+
+	require.NoError(t, os.WriteFile(cfg, []byte("timeout = \"5s\"\n"), 0644))
+	require.NoError(t, os.Remove(overlay))
+	changed, err = m.ChangedInputs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{cfg, overlay}, changed)
+}
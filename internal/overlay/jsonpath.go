@@ -0,0 +1,433 @@
+package overlay
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The subset of JSONPath (RFC 9535) understood in overlay targets:
+//
+//	$                     the document root
+//	.name  ['name']       a member of an object
+//	.*  [*]               every member or element
+//	[0]  [-1]             an element of an array
+//	['a','b']  [0,2]      several selectors at once
+//	..name  ..*  ..[0]    descendant lookup
+//	[?@.key]              elements whose key is present
+//	[?@.key == 'value']   elements whose key compares equal (==, !=)
+//
+// Filter paths may chain members (@.a.b) and compare against strings,
+// numbers, true, false and null.
+
+type selectorKind int
+
+const (
+	selectName selectorKind = iota
+	selectWildcard
+	selectIndex
+	selectFilter
+)
+
+type selector struct {
+	kind   selectorKind
+	name   string
+	index  int
+	filter *filter
+}
+
+type filter struct {
+	path  []string
+	op    string // "" tests for existence
+	value any
+}
+
+type segment struct {
+	descendant bool
+	selectors  []selector
+}
+
+type path []segment
+
+func parsePath(expr string) (path, error) {
+	p := &parser{src: strings.TrimSpace(expr)}
+	if !p.consume("$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", expr)
+	}
+	var segments path
+	for !p.done() {
+		seg, err := p.segment()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSONPath %q: %v", expr, err)
+		}
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) done() bool { return p.pos >= len(p.src) }
+
+func (p *parser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) consume(s string) bool {
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *parser) skipSpace() {
+	for !p.done() && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *parser) segment() (segment, error) {
+	var seg segment
+	switch {
+	case p.consume(".."):
+		seg.descendant = true
+		if p.peek() == '[' {
+			break
+		}
+		sel, err := p.dotSelector()
+		if err != nil {
+			return seg, err
+		}
+		seg.selectors = []selector{sel}
+		return seg, nil
+	case p.consume("."):
+		sel, err := p.dotSelector()
+		if err != nil {
+			return seg, err
+		}
+		seg.selectors = []selector{sel}
+		return seg, nil
+	case p.peek() != '[':
+		return seg, fmt.Errorf("unexpected %q at offset %d", p.peek(), p.pos)
+	}
+
+	p.consume("[")
+	for {
+		p.skipSpace()
+		sel, err := p.bracketSelector()
+		if err != nil {
+			return seg, err
+		}
+		seg.selectors = append(seg.selectors, sel)
+		p.skipSpace()
+		if p.consume("]") {
+			return seg, nil
+		}
+		if !p.consume(",") {
+			return seg, fmt.Errorf("expected , or ] at offset %d", p.pos)
+		}
+	}
+}
+
+func (p *parser) dotSelector() (selector, error) {
+	if p.consume("*") {
+		return selector{kind: selectWildcard}, nil
+	}
+	name := p.identifier()
+	if name == "" {
+		return selector{}, fmt.Errorf("expected a member name at offset %d", p.pos)
+	}
+	return selector{kind: selectName, name: name}, nil
+}
+
+func (p *parser) identifier() string {
+	start := p.pos
+	for !p.done() {
+		c := p.src[p.pos]
+		if c == '.' || c == '[' || c == ']' || c == ' ' || c == '=' || c == '!' || c == ',' || c == ')' {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) bracketSelector() (selector, error) {
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		return selector{kind: selectWildcard}, nil
+	case c == '\'' || c == '"':
+		s, err := p.quoted()
+		return selector{kind: selectName, name: s}, err
+	case c == '?':
+		p.pos++
+		f, err := p.filter()
+		return selector{kind: selectFilter, filter: f}, err
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.pos++
+		for !p.done() && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+		n, err := strconv.Atoi(p.src[start:p.pos])
+		return selector{kind: selectIndex, index: n}, err
+	}
+	return selector{}, fmt.Errorf("unsupported selector at offset %d", p.pos)
+}
+
+func (p *parser) quoted() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+	var b strings.Builder
+	for !p.done() {
+		c := p.src[p.pos]
+		p.pos++
+		switch {
+		case c == '\\' && !p.done():
+			b.WriteByte(p.src[p.pos])
+			p.pos++
+		case c == quote:
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+func (p *parser) filter() (*filter, error) {
+	p.skipSpace()
+	// the parenthesised form ?(@.a == 1) is still common in the wild
+	wrapped := p.consume("(")
+	p.skipSpace()
+	if !p.consume("@") {
+		return nil, fmt.Errorf("filter must start with @ at offset %d", p.pos)
+	}
+	f := &filter{}
+	for p.consume(".") {
+		name := p.identifier()
+		if name == "" {
+			return nil, fmt.Errorf("expected a member name at offset %d", p.pos)
+		}
+		f.path = append(f.path, name)
+	}
+	for p.peek() == '[' {
+		p.pos++
+		name, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume("]") {
+			return nil, fmt.Errorf("expected ] at offset %d", p.pos)
+		}
+		f.path = append(f.path, name)
+	}
+	p.skipSpace()
+	switch {
+	case p.consume("=="):
+		f.op = "=="
+	case p.consume("!="):
+		f.op = "!="
+	}
+	if f.op != "" {
+		p.skipSpace()
+		value, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		f.value = value
+		p.skipSpace()
+	}
+	if wrapped && !p.consume(")") {
+		return nil, fmt.Errorf("expected ) at offset %d", p.pos)
+	}
+	return f, nil
+}
+
+func (p *parser) literal() (any, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		return p.quoted()
+	case p.consume("true"):
+		return true, nil
+	case p.consume("false"):
+		return false, nil
+	case p.consume("null"):
+		return nil, nil
+	}
+	start := p.pos
+	for !p.done() && strings.IndexByte("-+.eE0123456789", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+	n, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+	if err != nil {
+		return nil, fmt.Errorf("expected a literal at offset %d", start)
+	}
+	return n, nil
+}
+
+// location is the chain of object keys (string) and array indexes (int)
+// leading from the root to a node.
+type location []any
+
+func (l location) String() string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, step := range l {
+		if i, ok := step.(int); ok {
+			fmt.Fprintf(&b, "[%d]", i)
+		} else {
+			fmt.Fprintf(&b, "[%q]", step)
+		}
+	}
+	return b.String()
+}
+
+// match returns the locations of every node selected by p, in document
+// order and without duplicates.
+func (p path) match(root any) []location {
+	nodes := []location{{}}
+	for _, seg := range p {
+		var next []location
+		for _, loc := range nodes {
+			value, _ := lookup(root, loc)
+			if seg.descendant {
+				walk(value, loc, func(l location, v any) {
+					next = append(next, seg.apply(v, l)...)
+				})
+			} else {
+				next = append(next, seg.apply(value, loc)...)
+			}
+		}
+		nodes = dedupe(next)
+	}
+	return nodes
+}
+
+func (s segment) apply(value any, loc location) []location {
+	var out []location
+	for _, sel := range s.selectors {
+		out = append(out, sel.apply(value, loc)...)
+	}
+	return out
+}
+
+func (s selector) apply(value any, loc location) []location {
+	child := func(step any) location {
+		l := make(location, len(loc), len(loc)+1)
+		copy(l, loc)
+		return append(l, step)
+	}
+	var out []location
+	switch v := value.(type) {
+	case map[string]any:
+		switch s.kind {
+		case selectName:
+			if _, ok := v[s.name]; ok {
+				out = append(out, child(s.name))
+			}
+		case selectWildcard, selectFilter:
+			for _, key := range sortedKeys(v) {
+				if s.kind == selectWildcard || s.filter.test(v[key]) {
+					out = append(out, child(key))
+				}
+			}
+		}
+	case []any:
+		switch s.kind {
+		case selectIndex:
+			i := s.index
+			if i < 0 {
+				i += len(v)
+			}
+			if i >= 0 && i < len(v) {
+				out = append(out, child(i))
+			}
+		case selectWildcard, selectFilter:
+			for i, item := range v {
+				if s.kind == selectWildcard || s.filter.test(item) {
+					out = append(out, child(i))
+				}
+			}
+		}
+	}
+	return out
+}
+
+func (f *filter) test(value any) bool {
+	for _, name := range f.path {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+		if value, ok = obj[name]; !ok {
+			return false
+		}
+	}
+	switch f.op {
+	case "==":
+		return value == f.value
+	case "!=":
+		return value != f.value
+	}
+	return true
+}
+
+// walk calls fn for value and all of its descendants, parents first.
+func walk(value any, loc location, fn func(location, any)) {
+	fn(loc, value)
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range sortedKeys(v) {
+			walk(v[key], append(loc[:len(loc):len(loc)], key), fn)
+		}
+	case []any:
+		for i, item := range v {
+			walk(item, append(loc[:len(loc):len(loc)], i), fn)
+		}
+	}
+}
+
+func lookup(root any, loc location) (any, bool) {
+	value := root
+	for _, step := range loc {
+		switch v := value.(type) {
+		case map[string]any:
+			key, _ := step.(string)
+			var ok bool
+			if value, ok = v[key]; !ok {
+				return nil, false
+			}
+		case []any:
+			i, ok := step.(int)
+			if !ok || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func dedupe(locs []location) []location {
+	seen := make(map[string]bool, len(locs))
+	out := locs[:0]
+	for _, loc := range locs {
+		key := loc.String()
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, loc)
+		}
+	}
+	return out
+}
@@ -0,0 +1,248 @@
+// Package overlay applies OpenAPI Overlay documents
+// (https://spec.openapis.org/overlay/v1.0.0) to an OpenAPI description, so
+// specs that cannot be edited at the source can still be patched before
+// conversion.
+package overlay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/oasdiff/yaml"
+)
+
+// Overlay is a parsed overlay document.
+type Overlay struct {
+	Version string   `json:"overlay"`
+	Info    Info     `json:"info"`
+	Extends string   `json:"extends,omitempty"`
+	Actions []Action `json:"actions"`
+
+	// Location and Digest identify the file the overlay was read from.
+	Location string `json:"-"`
+	Digest   string `json:"-"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Action updates or removes every node selected by Target.
+type Action struct {
+	Target      string `json:"target"`
+	Description string `json:"description,omitempty"`
+	Update      any    `json:"update,omitempty"`
+	Remove      bool   `json:"remove,omitempty"`
+}
+
+// Load reads a YAML or JSON overlay from path and validates its structure.
+func Load(path string) (*Overlay, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overlay: %v", err)
+	}
+	o, err := Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	sum := sha256.Sum256(raw)
+	o.Location = path
+	o.Digest = hex.EncodeToString(sum[:])
+	return o, nil
+}
+
+// Parse decodes a YAML or JSON overlay document.
+func Parse(raw []byte) (*Overlay, error) {
+	data, err := yaml.YAMLToJSON(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse overlay: %v", err)
+	}
+	var o Overlay
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("failed to parse overlay: %v", err)
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (o *Overlay) validate() error {
+	if !strings.HasPrefix(o.Version, "1.") {
+		return fmt.Errorf("unsupported overlay version %q, expected 1.x", o.Version)
+	}
+	if len(o.Actions) == 0 {
+		return fmt.Errorf("overlay has no actions")
+	}
+	for i, action := range o.Actions {
+		if _, err := parsePath(action.Target); err != nil {
+			return fmt.Errorf("action %d: %v", i+1, err)
+		}
+		if action.Update == nil && !action.Remove {
+			return fmt.Errorf("action %d (%s): needs either update or remove", i+1, action.Target)
+		}
+	}
+	return nil
+}
+
+// Apply runs the actions of o in order against doc, a document decoded from
+// JSON into maps and slices, and returns the patched document. Actions
+// whose target matches nothing are returned so callers can warn about them.
+//
+// An update is merged into objects recursively and appended to arrays. A
+// remove deletes the targeted nodes from their parents.
+func (o *Overlay) Apply(doc any) (any, []Action, error) {
+	var unmatched []Action
+	for i, action := range o.Actions {
+		target, err := parsePath(action.Target)
+		if err != nil {
+			return nil, nil, fmt.Errorf("action %d: %v", i+1, err)
+		}
+		locs := target.match(doc)
+		if len(locs) == 0 {
+			unmatched = append(unmatched, action)
+			continue
+		}
+		if action.Remove {
+			doc = remove(doc, locs)
+			continue
+		}
+		for _, loc := range locs {
+			if doc, err = update(doc, loc, action.Update); err != nil {
+				return nil, nil, fmt.Errorf("action %d (%s): %v", i+1, action.Target, err)
+			}
+		}
+	}
+	return doc, unmatched, nil
+}
+
+func update(doc any, loc location, value any) (any, error) {
+	current, _ := lookup(doc, loc)
+	switch current.(type) {
+	case map[string]any, []any:
+	default:
+		return nil, fmt.Errorf("%s is neither an object nor an array", loc)
+	}
+	return set(doc, loc, merge(current, value)), nil
+}
+
+// merge folds value into target: objects are merged key by key, arrays get
+// value appended, anything else is replaced.
+func merge(target, value any) any {
+	switch t := target.(type) {
+	case map[string]any:
+		v, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		for key, item := range v {
+			if existing, ok := t[key]; ok {
+				t[key] = merge(existing, item)
+			} else {
+				t[key] = clone(item)
+			}
+		}
+		return t
+	case []any:
+		if items, ok := value.([]any); ok {
+			return append(t, clone(items).([]any)...)
+		}
+		return append(t, clone(value))
+	}
+	return clone(value)
+}
+
+// remove deletes every location from doc. Locations are processed
+// children first and highest index first, so earlier deletions do not
+// shift the ones still pending.
+func remove(doc any, locs []location) any {
+	sorted := append([]location(nil), locs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return after(sorted[i], sorted[j]) })
+	for _, loc := range sorted {
+		if len(loc) == 0 {
+			doc = nil
+			continue
+		}
+		parentLoc, last := loc[:len(loc)-1], loc[len(loc)-1]
+		parent, ok := lookup(doc, parentLoc)
+		if !ok {
+			continue
+		}
+		switch p := parent.(type) {
+		case map[string]any:
+			delete(p, last.(string))
+		case []any:
+			i := last.(int)
+			if i < len(p) {
+				doc = set(doc, parentLoc, append(p[:i:i], p[i+1:]...))
+			}
+		}
+	}
+	return doc
+}
+
+// after orders a before b when a is deeper or has a larger index at the
+// first differing step.
+func after(a, b location) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if ai, ok := a[i].(int); ok {
+			if bi, ok := b[i].(int); ok && ai != bi {
+				return ai > bi
+			}
+		}
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return len(a) > len(b)
+}
+
+// set replaces the node at loc and returns the possibly new root.
+func set(doc any, loc location, value any) any {
+	if len(loc) == 0 {
+		return value
+	}
+	parent, _ := lookup(doc, loc[:len(loc)-1])
+	switch p := parent.(type) {
+	case map[string]any:
+		p[loc[len(loc)-1].(string)] = value
+	case []any:
+		p[loc[len(loc)-1].(int)] = value
+	}
+	return doc
+}
+
+// clone deep-copies JSON values so the same update applied to several
+// targets does not alias.
+func clone(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = clone(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = clone(item)
+		}
+		return out
+	}
+	return value
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
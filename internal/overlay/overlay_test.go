@@ -0,0 +1,153 @@
+package overlay
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petstore = `{
+  "openapi": "3.0.0",
+  "info": {"title": "Petstore", "version": "1.0.0"},
+  "paths": {
+    "/pets": {
+      "get": {"summary": "List pets", "tags": ["pets"], "parameters": [
+        {"name": "limit", "in": "query"},
+        {"name": "debug", "in": "query"},
+        {"name": "trace", "in": "header"}
+      ]},
+      "post": {"summary": "Create a pet", "tags": ["pets", "admin"]}
+    },
+    "/pets/{id}": {
+      "delete": {"summary": "Delete a pet", "tags": ["admin"]}
+    }
+  }
+}`
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var doc any
+	require.NoError(t, json.Unmarshal([]byte(s), &doc))
+	return doc
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"$", []string{`$`}},
+		{"$.info.title", []string{`$["info"]["title"]`}},
+		{"$.paths['/pets'].get", []string{`$["paths"]["/pets"]["get"]`}},
+		{`$.paths["/pets"].*.summary`, []string{`$["paths"]["/pets"]["get"]["summary"]`, `$["paths"]["/pets"]["post"]["summary"]`}},
+		{"$.paths['/pets'].get.parameters[-1]", []string{`$["paths"]["/pets"]["get"]["parameters"][2]`}},
+		{"$.paths['/pets'].get.parameters[0,2].name", []string{`$["paths"]["/pets"]["get"]["parameters"][0]["name"]`, `$["paths"]["/pets"]["get"]["parameters"][2]["name"]`}},
+		{"$.paths.*[?@.summary == 'Delete a pet']", []string{`$["paths"]["/pets/{id}"]["delete"]`}},
+		{"$.paths.*.get.parameters[?(@.in != 'query')]", []string{`$["paths"]["/pets"]["get"]["parameters"][2]`}},
+		{"$..delete", []string{`$["paths"]["/pets/{id}"]["delete"]`}},
+		{"$..tags[?@ == 'admin']", []string{`$["paths"]["/pets"]["post"]["tags"][1]`, `$["paths"]["/pets/{id}"]["delete"]["tags"][0]`}},
+		{"$.paths.missing", nil},
+	}
+	doc := decode(t, petstore)
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			p, err := parsePath(tt.target)
+			require.NoError(t, err)
+			var got []string
+			for _, loc := range p.match(doc) {
+				got = append(got, loc.String())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	for _, target := range []string{"", "info.title", "$.", "$[", "$['open", "$[?@.a == ]", "$[foo]"} {
+		_, err := parsePath(target)
+		assert.Error(t, err, target)
+	}
+}
+
+func TestApply(t *testing.T) {
+	o, err := Parse([]byte(`
+overlay: 1.0.0
+info:
+  title: Curate the petstore
+  version: 1.0.0
+actions:
+  - target: $.info
+    update:
+      description: Pets, curated
+  - target: $.paths['/pets'].get
+    update:
+      summary: List all pets
+      x-mcp-name: list_pets
+      tags: [public]
+  - target: $.paths['/pets'].get.parameters[?@.in == 'query']
+    update:
+      description: A query parameter
+  - target: $.paths.*.get.parameters[?@.name == 'debug']
+    remove: true
+  - target: $.paths['/pets/{id}'].delete
+    remove: true
+  - target: $.paths.*.patch
+    remove: true
+`))
+	require.NoError(t, err)
+
+	doc, unmatched, err := o.Apply(decode(t, petstore))
+	require.NoError(t, err)
+	require.Len(t, unmatched, 1)
+	assert.Equal(t, "$.paths.*.patch", unmatched[0].Target)
+
+	want := decode(t, `{
+  "openapi": "3.0.0",
+  "info": {"title": "Petstore", "version": "1.0.0", "description": "Pets, curated"},
+  "paths": {
+    "/pets": {
+      "get": {"summary": "List all pets", "x-mcp-name": "list_pets", "tags": ["pets", "public"], "parameters": [
+        {"name": "limit", "in": "query", "description": "A query parameter"},
+        {"name": "trace", "in": "header"}
+      ]},
+      "post": {"summary": "Create a pet", "tags": ["pets", "admin"]}
+    },
+    "/pets/{id}": {}
+  }
+}`)
+	assert.Equal(t, want, doc)
+}
+
+func TestApplyRemovesArrayElementsInReverse(t *testing.T) {
+	o := &Overlay{Version: "1.0.0", Actions: []Action{{Target: "$.items[?@.drop == true]", Remove: true}}}
+	doc, _, err := o.Apply(decode(t, `{"items": [{"drop": true}, {"id": 1}, {"drop": true}, {"drop": true}, {"id": 2}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, decode(t, `{"items": [{"id": 1}, {"id": 2}]}`), doc)
+}
+
+func TestApplyUpdateOnScalar(t *testing.T) {
+	o := &Overlay{Version: "1.0.0", Actions: []Action{{Target: "$.info.title", Update: "New"}}}
+	_, _, err := o.Apply(decode(t, petstore))
+	assert.ErrorContains(t, err, "neither an object nor an array")
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"version", "overlay: 2.0.0\nactions: [{target: $, remove: true}]", "unsupported overlay version"},
+		{"no actions", "overlay: 1.0.0\nactions: []", "no actions"},
+		{"bad target", "overlay: 1.0.0\nactions: [{target: info, remove: true}]", "must start with $"},
+		{"no operation", "overlay: 1.0.0\nactions: [{target: $.info}]", "needs either update or remove"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.doc))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package pkgmgr
+
+import "fmt"
+
+// Dependency is a requirement of a generated project with the version range
+// its templates were tested against. Name may carry extras, as in
+// "jsonschema[format-nongpl]".
+type Dependency struct {
+	Name       string
+	Constraint string
+}
+
+// String renders the dependency as a PEP 508 requirement, e.g. "mcp>=1.2.0,<2.0.0".
+func (d Dependency) String() string {
+	return d.Name + d.Constraint
+}
+
+// managed maps each revision of the server templates to the dependency set
+// known to work with it. Add a new revision instead of editing an existing
+// one so projects generated earlier stay reproducible.
+var managed = map[string][]Dependency{
+	"1": {
+		{Name: "mcp", Constraint: ">=1.2.0,<2.0.0"},
+		{Name: "aiohttp", Constraint: ">=3.9.0,<4.0.0"},
+	},
+	// tool annotations
+	"2": {
+		{Name: "mcp", Constraint: ">=1.9.0,<2.0.0"},
+		{Name: "aiohttp", Constraint: ">=3.9.0,<4.0.0"},
+	},
+	// structured tool output
+	"3": {
+		{Name: "mcp", Constraint: ">=1.10.0,<2.0.0"},
+		{Name: "aiohttp", Constraint: ">=3.9.0,<4.0.0"},
+	},
+	// argument validation
+	"4": {
+		{Name: "mcp", Constraint: ">=1.10.0,<2.0.0"},
+		{Name: "aiohttp", Constraint: ">=3.9.0,<4.0.0"},
+		{Name: "jsonschema", Constraint: ">=4.20.0,<5.0.0"},
+	},
+	// format checks of argument validation, such as date-time and uri
+	"5": {
+		{Name: "mcp", Constraint: ">=1.10.0,<2.0.0"},
+		{Name: "aiohttp", Constraint: ">=3.9.0,<4.0.0"},
+		{Name: "jsonschema[format-nongpl]", Constraint: ">=4.20.0,<5.0.0"},
+	},
+}
+
+// Managed returns the dependencies pinned for templateVersion.
+func Managed(templateVersion string) ([]Dependency, error) {
+	deps, ok := managed[templateVersion]
+	if !ok {
+		return nil, fmt.Errorf("no managed dependencies for template version %q", templateVersion)
+	}
+	return append([]Dependency(nil), deps...), nil
+}
+
+// Requirements renders deps as PEP 508 requirement strings.
+func Requirements(deps []Dependency) []string {
+	reqs := make([]string, len(deps))
+	for i, d := range deps {
+		reqs[i] = d.String()
+	}
+	return reqs
+}
+
+// WriteDependencies records deps in pyproject.toml without invoking the
+// toolchain, so a project can be generated without network access.
+func WriteDependencies(dir string, deps []Dependency) error {
+	if len(deps) == 0 {
+		return nil
+	}
+	if err := addToPyProject(dir, Requirements(deps)...); err != nil {
+		return fmt.Errorf("failed to write dependencies: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package pkgmgr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Fake is a Manager that never shells out. Init writes the same scaffold as
+// the pip backend and Add edits pyproject.toml directly; every call is
+// recorded in Calls so tests can assert on the generation flow.
+type Fake struct {
+	Calls []string
+	// Err, when set, is returned by Sync.
+	Err error
+}
+
+func (m *Fake) record(format string, args ...interface{}) {
+	m.Calls = append(m.Calls, fmt.Sprintf(format, args...))
+}
+
+func (m *Fake) Name() string {
+	return "fake"
+}
+
+func (m *Fake) EnsureInstalled() error {
+	m.record("ensure")
+	return nil
+}
+
+func (m *Fake) Init(dir, name string) error {
+	m.record("init %s", name)
+	return scaffold(dir, name, hatchling)
+}
+
+func (m *Fake) Add(dir string, deps ...string) error {
+	m.record("add %s", strings.Join(deps, " "))
+	if len(deps) == 0 {
+		return nil
+	}
+	return addToPyProject(dir, deps...)
+}
+
+func (m *Fake) Sync(dir string) error {
+	m.record("sync")
+	return m.Err
+}
+
+func (m *Fake) SyncCommand() string {
+	return "fake sync"
+}
+
+func (m *Fake) RunCommand(dir, binary string) []string {
+	return []string{"fake", "run", binary}
+}
+
+var _ Manager = new(Fake)
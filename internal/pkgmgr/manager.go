@@ -0,0 +1,127 @@
+package pkgmgr
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Manager drives the Python toolchain used to scaffold, install and run a
+// generated project.
+type Manager interface {
+	// Name returns the identifier used to select the manager, e.g. "uv".
+	Name() string
+	// EnsureInstalled reports an error when the toolchain is missing or too old.
+	EnsureInstalled() error
+	// Init scaffolds a packaged application called name in dir.
+	Init(dir, name string) error
+	// Add declares dependencies for the project in dir.
+	Add(dir string, deps ...string) error
+	// Sync installs the declared dependencies of the project in dir.
+	Sync(dir string) error
+	// SyncCommand returns the shell command users run to install dependencies.
+	SyncCommand() string
+	// RunCommand returns the command line that starts binary from the project in dir.
+	RunCommand(dir, binary string) []string
+}
+
+// Command is a single invocation of an external tool.
+type Command struct {
+	Dir  string
+	Name string
+	Args []string
+	// Stream forwards the command output to the user instead of discarding it.
+	Stream bool
+}
+
+func (c Command) String() string {
+	return strings.TrimSpace(c.Name + " " + strings.Join(c.Args, " "))
+}
+
+// Runner executes commands on behalf of a Manager.
+type Runner interface {
+	Run(cmd Command) error
+	Output(cmd Command) ([]byte, error)
+}
+
+// ExecRunner runs commands with os/exec.
+type ExecRunner struct {
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+func (r ExecRunner) Run(c Command) error {
+	cmd := exec.Command(c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	if c.Stream {
+		cmd.Stdout = r.stdout()
+		cmd.Stderr = r.stderr()
+	}
+	return cmd.Run()
+}
+
+func (r ExecRunner) Output(c Command) ([]byte, error) {
+	cmd := exec.Command(c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	return cmd.Output()
+}
+
+func (r ExecRunner) stdout() io.Writer {
+	if r.Stdout != nil {
+		return r.Stdout
+	}
+	return os.Stdout
+}
+
+func (r ExecRunner) stderr() io.Writer {
+	if r.Stderr != nil {
+		return r.Stderr
+	}
+	return os.Stderr
+}
+
+var constructors = map[string]func(Runner) Manager{
+	"uv":     func(r Runner) Manager { return &UV{runner: r} },
+	"poetry": func(r Runner) Manager { return &Poetry{runner: r} },
+	"pip":    func(r Runner) Manager { return &Pip{runner: r} },
+}
+
+// Default is the manager used when none is configured.
+const Default = "uv"
+
+// Names lists the supported manager identifiers.
+func Names() []string {
+	names := make([]string, 0, len(constructors))
+	for name := range constructors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New returns the manager registered under name. A nil runner falls back to
+// ExecRunner.
+func New(name string, runner Runner) (Manager, error) {
+	if name == "" {
+		name = Default
+	}
+	ctor, ok := constructors[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown package manager %q, expected one of %s", name, strings.Join(Names(), ", "))
+	}
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+	return ctor(runner), nil
+}
+
+// run executes cmd and wraps failures with what the manager was trying to do.
+func run(r Runner, cmd Command, action string) error {
+	if err := r.Run(cmd); err != nil {
+		return fmt.Errorf("failed to %s (%s): %v", action, cmd, err)
+	}
+	return nil
+}
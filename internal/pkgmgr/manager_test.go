@@ -0,0 +1,167 @@
+package pkgmgr
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pelletier/go-toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingRunner struct {
+	commands []string
+	dirs     []string
+	output   string
+	err      error
+}
+
+func (r *recordingRunner) Run(cmd Command) error {
+	r.commands = append(r.commands, cmd.String())
+	r.dirs = append(r.dirs, cmd.Dir)
+	return r.err
+}
+
+func (r *recordingRunner) Output(cmd Command) ([]byte, error) {
+	r.commands = append(r.commands, cmd.String())
+	return []byte(r.output), r.err
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range []string{"", "uv", "poetry", "pip"} {
+		m, err := New(name, &recordingRunner{})
+		require.NoError(t, err)
+		if name == "" {
+			name = Default
+		}
+		assert.Equal(t, name, m.Name())
+	}
+
+	_, err := New("conda", nil)
+	require.Error(t, err)
+}
+
+func TestUVCommands(t *testing.T) {
+	runner := &recordingRunner{}
+	m, err := New("uv", runner)
+	require.NoError(t, err)
+
+	require.NoError(t, m.Init("/tmp/p", "demo"))
+	require.NoError(t, m.Add("/tmp/p", "mcp", "aiohttp"))
+	require.NoError(t, m.Sync("/tmp/p"))
+
+	assert.Equal(t, []string{
+		"uv init --name demo --package --app --quiet",
+		"uv add mcp aiohttp",
+		"uv sync --dev --all-extras",
+	}, runner.commands)
+	assert.Equal(t, []string{"uv", "--directory", "/tmp/p", "run", "demo"}, m.RunCommand("/tmp/p", "demo"))
+}
+
+func TestUVEnsureInstalled(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		err     error
+		wantErr bool
+	}{
+		{name: "recent version", output: "uv 0.5.1 (abc 2024-11-01)"},
+		{name: "minimum version", output: "uv 0.4.10"},
+		{name: "too old", output: "uv 0.4.9", wantErr: true},
+		{name: "unparseable", output: "something else", wantErr: true},
+		{name: "missing binary", err: errors.New("not found"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &UV{runner: &recordingRunner{output: tt.output, err: tt.err}}
+			err := m.EnsureInstalled()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPoetryCommands(t *testing.T) {
+	dir := t.TempDir()
+	runner := &recordingRunner{}
+	m, err := New("poetry", runner)
+	require.NoError(t, err)
+
+	require.NoError(t, m.Init(dir, "demo-server"))
+	require.NoError(t, m.Add(dir, "mcp"))
+	require.NoError(t, m.Sync(dir))
+
+	assert.Equal(t, []string{"poetry add mcp", "poetry install"}, runner.commands)
+	assert.FileExists(t, filepath.Join(dir, "src", "demo_server", "__init__.py"))
+
+	tree, err := toml.LoadFile(filepath.Join(dir, "pyproject.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, "poetry.core.masonry.api", tree.Get("build-system.build-backend"))
+}
+
+func TestPipAddWritesPyProject(t *testing.T) {
+	dir := t.TempDir()
+	m, err := New("pip", &recordingRunner{})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Init(dir, "demo"))
+	require.NoError(t, m.Add(dir, "mcp", "aiohttp>=3.9"))
+	require.NoError(t, m.Add(dir, "aiohttp>=3.10"))
+
+	tree, err := toml.LoadFile(filepath.Join(dir, "pyproject.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"mcp", "aiohttp>=3.10"}, tree.Get("project.dependencies"))
+	assert.Equal(t, "demo:main", tree.Get("project.scripts.demo"))
+}
+
+func TestPipSyncCreatesVenvOnce(t *testing.T) {
+	dir := t.TempDir()
+	runner := &recordingRunner{}
+	m := &Pip{runner: runner}
+
+	require.NoError(t, m.Sync(dir))
+	require.Len(t, runner.commands, 2)
+	assert.Contains(t, runner.commands[0], "-m venv .venv")
+	assert.Contains(t, runner.commands[1], "-m pip install --editable .")
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, venvDir), 0755))
+	runner.commands = nil
+	require.NoError(t, m.Sync(dir))
+	assert.Len(t, runner.commands, 1)
+}
+
+func TestPipSyncRelativeDir(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { os.Chdir(wd) })
+	require.NoError(t, os.MkdirAll(filepath.Join("demo", venvDir), 0755))
+
+	runner := &recordingRunner{}
+	m := &Pip{runner: runner}
+	require.NoError(t, m.Sync("demo"))
+	require.Len(t, runner.dirs, 1)
+	assert.Equal(t, "demo", runner.dirs[0])
+	// the executable is resolved against the directory the command runs in
+	assert.Equal(t, venvExecutable(".", "python")+" -m pip install --editable .", runner.commands[0])
+}
+
+func TestRequirementName(t *testing.T) {
+	assert.Equal(t, "mcp", requirementName("mcp>=1.2,<2"))
+	assert.Equal(t, "aiohttp", requirementName("aiohttp"))
+	assert.Equal(t, "typing_extensions", requirementName("Typing-Extensions ; python_version<'3.11'"))
+}
+
+func TestManaged(t *testing.T) {
+	deps, err := Managed("1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mcp>=1.2.0,<2.0.0", "aiohttp>=3.9.0,<4.0.0"}, Requirements(deps))
+
+	_, err = Managed("0")
+	require.Error(t, err)
+}
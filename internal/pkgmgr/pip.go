@@ -0,0 +1,91 @@
+package pkgmgr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// venvDir is the virtual environment created inside the project by Pip.
+const venvDir = ".venv"
+
+// Pip manages projects with the standard library venv module and pip. It
+// has no dependency resolver of its own, so Add edits pyproject.toml and
+// Sync installs the project into the virtual environment.
+type Pip struct {
+	runner Runner
+}
+
+func (m *Pip) Name() string {
+	return "pip"
+}
+
+func (m *Pip) EnsureInstalled() error {
+	if _, err := m.runner.Output(Command{Name: pythonExecutable(), Args: []string{"-m", "venv", "--help"}}); err != nil {
+		return fmt.Errorf("python 3 with the venv module is required but not installed.\nTo install, visit: https://www.python.org/downloads/")
+	}
+	return nil
+}
+
+func (m *Pip) Init(dir, name string) error {
+	if err := scaffold(dir, name, hatchling); err != nil {
+		return fmt.Errorf("failed to initialize project: %v", err)
+	}
+	return nil
+}
+
+func (m *Pip) Add(dir string, deps ...string) error {
+	if len(deps) == 0 {
+		return nil
+	}
+	if err := addToPyProject(dir, deps...); err != nil {
+		return fmt.Errorf("failed to add dependencies: %v", err)
+	}
+	return nil
+}
+
+func (m *Pip) Sync(dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, venvDir)); os.IsNotExist(err) {
+		if err := run(m.runner, Command{
+			Dir:  dir,
+			Name: pythonExecutable(),
+			Args: []string{"-m", "venv", venvDir},
+		}, "create virtual environment"); err != nil {
+			return err
+		}
+	}
+	// the command runs in dir, so its path is relative to dir
+	return run(m.runner, Command{
+		Dir:    dir,
+		Name:   venvExecutable(".", "python"),
+		Args:   []string{"-m", "pip", "install", "--editable", "."},
+		Stream: true,
+	}, "sync dependencies")
+}
+
+func (m *Pip) SyncCommand() string {
+	return fmt.Sprintf("%s -m venv %s && %s -m pip install --editable .", pythonExecutable(), venvDir, venvExecutable(".", "python"))
+}
+
+func (m *Pip) RunCommand(dir, binary string) []string {
+	return []string{venvExecutable(dir, binary)}
+}
+
+func pythonExecutable() string {
+	if runtime.GOOS == "windows" {
+		return "python"
+	}
+	return "python3"
+}
+
+// venvExecutable returns the path of an executable installed into the
+// project's virtual environment.
+func venvExecutable(dir, name string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(dir, venvDir, "Scripts", name+".exe")
+	}
+	return filepath.Join(dir, venvDir, "bin", name)
+}
+
+var _ Manager = new(Pip)
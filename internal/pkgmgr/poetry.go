@@ -0,0 +1,57 @@
+package pkgmgr
+
+import "fmt"
+
+// Poetry manages projects with Poetry 2.x, which reads the PEP 621 [project]
+// table written by the scaffold.
+type Poetry struct {
+	runner Runner
+}
+
+func (m *Poetry) Name() string {
+	return "poetry"
+}
+
+func (m *Poetry) EnsureInstalled() error {
+	if _, err := m.runner.Output(Command{Name: "poetry", Args: []string{"--version"}}); err != nil {
+		return fmt.Errorf("poetry is required but not installed.\nTo install, visit: https://python-poetry.org/docs/#installation")
+	}
+	return nil
+}
+
+func (m *Poetry) Init(dir, name string) error {
+	if err := scaffold(dir, name, poetryCore); err != nil {
+		return fmt.Errorf("failed to initialize project: %v", err)
+	}
+	return nil
+}
+
+func (m *Poetry) Add(dir string, deps ...string) error {
+	if len(deps) == 0 {
+		return nil
+	}
+	return run(m.runner, Command{
+		Dir:  dir,
+		Name: "poetry",
+		Args: append([]string{"add"}, deps...),
+	}, "add dependencies")
+}
+
+func (m *Poetry) Sync(dir string) error {
+	return run(m.runner, Command{
+		Dir:    dir,
+		Name:   "poetry",
+		Args:   []string{"install"},
+		Stream: true,
+	}, "sync dependencies")
+}
+
+func (m *Poetry) SyncCommand() string {
+	return "poetry install"
+}
+
+func (m *Poetry) RunCommand(dir, binary string) []string {
+	return []string{"poetry", "--directory", dir, "run", binary}
+}
+
+var _ Manager = new(Poetry)
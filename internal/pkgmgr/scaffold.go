@@ -0,0 +1,129 @@
+package pkgmgr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// buildBackend is the [build-system] table written into a scaffolded
+// pyproject.toml.
+type buildBackend struct {
+	requires []string
+	backend  string
+}
+
+var (
+	hatchling  = buildBackend{requires: []string{"hatchling"}, backend: "hatchling.build"}
+	poetryCore = buildBackend{requires: []string{"poetry-core>=2.0.0,<3.0.0"}, backend: "poetry.core.masonry.api"}
+)
+
+// PackageName returns the importable module name for a project name.
+func PackageName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, "-", "_")
+	return strings.ReplaceAll(name, ".", "_")
+}
+
+// scaffold lays out the same src-layout application `uv init --package --app`
+// produces, for toolchains that cannot do it themselves.
+func scaffold(dir, name string, build buildBackend) error {
+	pkg := PackageName(name)
+	pkgDir := filepath.Join(dir, "src", pkg)
+	if err := os.MkdirAll(pkgDir, 0755); err != nil {
+		return fmt.Errorf("failed to create package directory: %v", err)
+	}
+
+	pyproject := filepath.Join(dir, "pyproject.toml")
+	if _, err := os.Stat(pyproject); err == nil {
+		return fmt.Errorf("project already initialized: %s exists", pyproject)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[project]\n")
+	fmt.Fprintf(&b, "name = %q\n", name)
+	fmt.Fprintf(&b, "version = \"0.1.0\"\n")
+	fmt.Fprintf(&b, "description = \"Add your description here\"\n")
+	fmt.Fprintf(&b, "readme = \"README.md\"\n")
+	fmt.Fprintf(&b, "requires-python = \">=3.10\"\n")
+	fmt.Fprintf(&b, "dependencies = []\n\n")
+	fmt.Fprintf(&b, "[project.scripts]\n")
+	fmt.Fprintf(&b, "%s = \"%s:main\"\n\n", name, pkg)
+	fmt.Fprintf(&b, "[build-system]\n")
+	fmt.Fprintf(&b, "requires = [%s]\n", quoteList(build.requires))
+	fmt.Fprintf(&b, "build-backend = %q\n", build.backend)
+
+	files := map[string]string{
+		pyproject:                             b.String(),
+		filepath.Join(dir, "README.md"):       "",
+		filepath.Join(pkgDir, "__init__.py"):  "def main() -> None:\n    print(\"Hello from " + name + "!\")\n",
+		filepath.Join(dir, ".python-version"): "3.12\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// addToPyProject merges deps into project.dependencies of the pyproject.toml
+// in dir, replacing any existing requirement on the same distribution.
+func addToPyProject(dir string, deps ...string) error {
+	path := filepath.Join(dir, "pyproject.toml")
+	tree, err := toml.LoadFile(path)
+	if err != nil {
+		return fmt.Errorf("pyproject.toml not found: %v", err)
+	}
+
+	var current []string
+	if existing, ok := tree.Get("project.dependencies").([]interface{}); ok {
+		for _, d := range existing {
+			if s, ok := d.(string); ok {
+				current = append(current, s)
+			}
+		}
+	}
+	for _, dep := range deps {
+		replaced := false
+		for i, existing := range current {
+			if requirementName(existing) == requirementName(dep) {
+				current[i] = dep
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			current = append(current, dep)
+		}
+	}
+	tree.Set("project.dependencies", current)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to open pyproject.toml: %v", err)
+	}
+	defer file.Close()
+	return toml.NewEncoder(file).Encode(tree)
+}
+
+// requirementName extracts the normalized distribution name of a PEP 508
+// requirement such as "mcp>=1.2,<2".
+func requirementName(req string) string {
+	end := strings.IndexAny(req, "<>=!~ ;[@")
+	if end >= 0 {
+		req = req[:end]
+	}
+	return PackageName(strings.TrimSpace(req))
+}
+
+func quoteList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = fmt.Sprintf("%q", item)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,97 @@
+package pkgmgr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const (
+	MinUVVersion = "0.4.10"
+)
+
+// UV manages projects with astral's uv.
+type UV struct {
+	runner Runner
+}
+
+func (m *UV) Name() string {
+	return "uv"
+}
+
+func (m *UV) EnsureInstalled() error {
+	version, err := m.checkVersion(MinUVVersion)
+	if err != nil || version == "" {
+		return fmt.Errorf("uv >= %s is required but not installed.\nTo install, visit: https://github.com/astral-sh/uv", MinUVVersion)
+	}
+	return nil
+}
+
+// checkVersion returns the installed uv version string when it satisfies
+// requiredVersion, and an empty string when uv is missing or too old.
+func (m *UV) checkVersion(requiredVersion string) (string, error) {
+	output, err := m.runner.Output(Command{Name: "uv", Args: []string{"--version"}})
+	if err != nil {
+		return "", fmt.Errorf("failed to check uv version: %v", err)
+	}
+
+	version := strings.TrimSpace(string(output))
+	re := regexp.MustCompile(`uv (\d+\.\d+\.\d+)`)
+	matches := re.FindStringSubmatch(version)
+	if len(matches) < 2 {
+		return "", nil
+	}
+
+	reqVer, err := semver.NewVersion(requiredVersion)
+	if err != nil {
+		return "", err
+	}
+	curVer, err := semver.NewVersion(matches[1])
+	if err != nil {
+		return "", nil
+	}
+	if curVer.Compare(reqVer) >= 0 {
+		return version, nil
+	}
+	return "", nil
+}
+
+func (m *UV) Init(dir, name string) error {
+	return run(m.runner, Command{
+		Dir:  dir,
+		Name: "uv",
+		Args: []string{"init", "--name", name, "--package", "--app", "--quiet"},
+	}, "initialize project")
+}
+
+func (m *UV) Add(dir string, deps ...string) error {
+	if len(deps) == 0 {
+		return nil
+	}
+	return run(m.runner, Command{
+		Dir:  dir,
+		Name: "uv",
+		Args: append([]string{"add"}, deps...),
+	}, "add dependencies")
+}
+
+func (m *UV) Sync(dir string) error {
+	return run(m.runner, Command{
+		Dir:    dir,
+		Name:   "uv",
+		Args:   []string{"sync", "--dev", "--all-extras"},
+		Stream: true,
+	}, "sync dependencies")
+}
+
+func (m *UV) SyncCommand() string {
+	return "uv sync --dev --all-extras"
+}
+
+func (m *UV) RunCommand(dir, binary string) []string {
+	return []string{"uv", "--directory", dir, "run", binary}
+}
+
+var _ Manager = new(UV)
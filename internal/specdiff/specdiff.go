@@ -0,0 +1,199 @@
+package specdiff
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+// Kind classifies a change between two converted specs.
+type Kind string
+
+const (
+	ToolAdded           Kind = "tool-added"
+	ToolRemoved         Kind = "tool-removed"
+	ToolRenamed         Kind = "tool-renamed"
+	ArgumentAdded       Kind = "argument-added"
+	ArgumentRemoved     Kind = "argument-removed"
+	ArgumentRequired    Kind = "argument-required"
+	ArgumentOptional    Kind = "argument-optional"
+	ArgumentTypeChanged Kind = "argument-type-changed"
+)
+
+// Change is one difference in the MCP surface of two specs.
+type Change struct {
+	Kind     Kind   `json:"kind"`
+	Tool     string `json:"tool"`
+	Argument string `json:"argument,omitempty"`
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+	Breaking bool   `json:"breaking"`
+}
+
+// String describes the change in one line.
+func (c Change) String() string {
+	switch c.Kind {
+	case ToolAdded:
+		return fmt.Sprintf("tool %s added", c.Tool)
+	case ToolRemoved:
+		return fmt.Sprintf("tool %s removed", c.Tool)
+	case ToolRenamed:
+		return fmt.Sprintf("tool %s renamed to %s", c.From, c.To)
+	case ArgumentAdded:
+		if c.Breaking {
+			return fmt.Sprintf("required argument %s added to %s", c.Argument, c.Tool)
+		}
+		return fmt.Sprintf("optional argument %s added to %s", c.Argument, c.Tool)
+	case ArgumentRemoved:
+		return fmt.Sprintf("argument %s removed from %s", c.Argument, c.Tool)
+	case ArgumentRequired:
+		return fmt.Sprintf("argument %s of %s is now required", c.Argument, c.Tool)
+	case ArgumentOptional:
+		return fmt.Sprintf("argument %s of %s is now optional", c.Argument, c.Tool)
+	case ArgumentTypeChanged:
+		return fmt.Sprintf("argument %s of %s changed type from %s to %s", c.Argument, c.Tool, display(c.From), display(c.To))
+	}
+	return string(c.Kind)
+}
+
+// Report lists the changes from one spec version to the next.
+type Report struct {
+	Changes  []Change `json:"changes"`
+	Breaking bool     `json:"breaking"`
+}
+
+// Compare reports how the tools of next differ from those of prev. A tool
+// that disappears while another with a new name serves the same method and
+// path is reported as a rename.
+func Compare(prev, next *core.TemplateData) *Report {
+	report := &Report{Changes: []Change{}}
+	oldTools := indexTools(prev)
+	newTools := indexTools(next)
+
+	renamedTo := make(map[string]string)
+	for _, name := range sortedNames(oldTools) {
+		if _, ok := newTools[name]; ok {
+			continue
+		}
+		old := oldTools[name]
+		for _, candidate := range sortedNames(newTools) {
+			tool := newTools[candidate]
+			if _, existed := oldTools[candidate]; existed {
+				continue
+			}
+			if tool.Method == old.Method && tool.Path == old.Path {
+				renamedTo[name] = candidate
+				break
+			}
+		}
+	}
+	renamedFrom := make(map[string]string, len(renamedTo))
+	for from, to := range renamedTo {
+		renamedFrom[to] = from
+	}
+
+	for _, name := range sortedNames(oldTools) {
+		if _, ok := newTools[name]; ok {
+			report.add(compareArguments(name, oldTools[name], newTools[name])...)
+			continue
+		}
+		if to, ok := renamedTo[name]; ok {
+			report.add(Change{Kind: ToolRenamed, Tool: to, From: name, To: to, Breaking: true})
+			report.add(compareArguments(to, oldTools[name], newTools[to])...)
+			continue
+		}
+		report.add(Change{Kind: ToolRemoved, Tool: name, Breaking: true})
+	}
+	for _, name := range sortedNames(newTools) {
+		if _, ok := oldTools[name]; ok {
+			continue
+		}
+		if _, ok := renamedFrom[name]; ok {
+			continue
+		}
+		report.add(Change{Kind: ToolAdded, Tool: name})
+	}
+	return report
+}
+
+func (r *Report) add(changes ...Change) {
+	for _, c := range changes {
+		r.Changes = append(r.Changes, c)
+		r.Breaking = r.Breaking || c.Breaking
+	}
+}
+
+func compareArguments(tool string, prev, next core.Tool) []Change {
+	var changes []Change
+	oldArgs := indexArguments(prev)
+	newArgs := indexArguments(next)
+
+	for _, name := range sortedNames(oldArgs) {
+		old := oldArgs[name]
+		arg, ok := newArgs[name]
+		if !ok {
+			changes = append(changes, Change{Kind: ArgumentRemoved, Tool: tool, Argument: name, Breaking: true})
+			continue
+		}
+		if !old.Required && arg.Required {
+			changes = append(changes, Change{Kind: ArgumentRequired, Tool: tool, Argument: name, Breaking: true})
+		}
+		if old.Required && !arg.Required {
+			changes = append(changes, Change{Kind: ArgumentOptional, Tool: tool, Argument: name})
+		}
+		if old.Type != arg.Type {
+			changes = append(changes, Change{
+				Kind:     ArgumentTypeChanged,
+				Tool:     tool,
+				Argument: name,
+				From:     old.Type,
+				To:       arg.Type,
+				Breaking: true,
+			})
+		}
+	}
+	for _, name := range sortedNames(newArgs) {
+		if _, ok := oldArgs[name]; ok {
+			continue
+		}
+		arg := newArgs[name]
+		changes = append(changes, Change{Kind: ArgumentAdded, Tool: tool, Argument: name, Breaking: arg.Required})
+	}
+	return changes
+}
+
+func indexTools(data *core.TemplateData) map[string]core.Tool {
+	tools := make(map[string]core.Tool)
+	if data == nil {
+		return tools
+	}
+	for _, tool := range data.Tools {
+		tools[tool.Name] = tool
+	}
+	return tools
+}
+
+func indexArguments(tool core.Tool) map[string]core.Argument {
+	args := make(map[string]core.Argument, len(tool.Arguments))
+	for _, arg := range tool.Arguments {
+		args[arg.Name] = arg
+	}
+	return args
+}
+
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func display(typ string) string {
+	if typ == "" {
+		return "any"
+	}
+	return typ
+}
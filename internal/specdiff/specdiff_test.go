@@ -0,0 +1,117 @@
+package specdiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+func TestCompare(t *testing.T) {
+	getPet := core.Tool{
+		Name:   "get_pet_by_petId",
+		Method: "GET",
+		Path:   "/pet/{petId}",
+		Arguments: []core.Argument{
+			{Name: "petId", Required: true, Type: "integer"},
+		},
+	}
+	updatePet := core.Tool{
+		Name:   "put_pet",
+		Method: "PUT",
+		Path:   "/pet",
+		Arguments: []core.Argument{
+			{Name: "name", Type: "string"},
+			{Name: "status", Type: "string"},
+			{Name: "tags", Required: true, Type: "array"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		prev         []core.Tool
+		next         []core.Tool
+		want         []Change
+		wantBreaking bool
+	}{
+		{
+			name: "identical",
+			prev: []core.Tool{getPet},
+			next: []core.Tool{getPet},
+			want: []Change{},
+		},
+		{
+			name: "tool added",
+			prev: []core.Tool{getPet},
+			next: []core.Tool{getPet, updatePet},
+			want: []Change{{Kind: ToolAdded, Tool: "put_pet"}},
+		},
+		{
+			name:         "tool removed",
+			prev:         []core.Tool{getPet, updatePet},
+			next:         []core.Tool{getPet},
+			want:         []Change{{Kind: ToolRemoved, Tool: "put_pet", Breaking: true}},
+			wantBreaking: true,
+		},
+		{
+			name: "tool renamed",
+			prev: []core.Tool{getPet},
+			next: []core.Tool{func() core.Tool {
+				renamed := getPet
+				renamed.Name = "find_pet"
+				return renamed
+			}()},
+			want:         []Change{{Kind: ToolRenamed, Tool: "find_pet", From: "get_pet_by_petId", To: "find_pet", Breaking: true}},
+			wantBreaking: true,
+		},
+		{
+			name: "argument changes",
+			prev: []core.Tool{updatePet},
+			next: []core.Tool{{
+				Name:   "put_pet",
+				Method: "PUT",
+				Path:   "/pet",
+				Arguments: []core.Argument{
+					{Name: "name", Required: true, Type: "string"},
+					{Name: "status", Type: "integer"},
+					{Name: "tags", Type: "array"},
+					{Name: "category", Type: "object"},
+					{Name: "owner", Required: true, Type: "string"},
+				},
+			}},
+			want: []Change{
+				{Kind: ArgumentRequired, Tool: "put_pet", Argument: "name", Breaking: true},
+				{Kind: ArgumentTypeChanged, Tool: "put_pet", Argument: "status", From: "string", To: "integer", Breaking: true},
+				{Kind: ArgumentOptional, Tool: "put_pet", Argument: "tags"},
+				{Kind: ArgumentAdded, Tool: "put_pet", Argument: "category"},
+				{Kind: ArgumentAdded, Tool: "put_pet", Argument: "owner", Breaking: true},
+			},
+			wantBreaking: true,
+		},
+		{
+			name: "argument removed",
+			prev: []core.Tool{updatePet},
+			next: []core.Tool{{
+				Name:      "put_pet",
+				Method:    "PUT",
+				Path:      "/pet",
+				Arguments: updatePet.Arguments[:2],
+			}},
+			want:         []Change{{Kind: ArgumentRemoved, Tool: "put_pet", Argument: "tags", Breaking: true}},
+			wantBreaking: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Compare(&core.TemplateData{Tools: tt.prev}, &core.TemplateData{Tools: tt.next})
+			assert.Equal(t, tt.want, report.Changes)
+			assert.Equal(t, tt.wantBreaking, report.Breaking)
+		})
+	}
+}
+
+func TestChangeString(t *testing.T) {
+	c := Change{Kind: ArgumentTypeChanged, Tool: "put_pet", Argument: "status", To: "integer"}
+	assert.Equal(t, "argument status of put_pet changed type from any to integer", c.String())
+}
@@ -0,0 +1,103 @@
+// Package stats estimates how much of a model's context the tool catalog of
+// a generated server takes up.
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+// Tool is the cost of one tool definition in the tools/list result.
+type Tool struct {
+	Name   string `json:"name"`
+	Tokens int    `json:"tokens"`
+	Bytes  int    `json:"bytes"`
+	// Expensive marks tools costing more than twice the average.
+	Expensive bool `json:"expensive"`
+}
+
+// Report is the estimated size of the tools/list result, most expensive
+// tools first.
+type Report struct {
+	Tools  []Tool `json:"tools"`
+	Tokens int    `json:"tokens"`
+	Bytes  int    `json:"bytes"`
+	// Budget is the configured token limit, 0 when there is none.
+	Budget int `json:"budget,omitempty"`
+}
+
+// OverBudget reports whether the catalog exceeds the budget.
+func (r *Report) OverBudget() bool {
+	return r.Budget > 0 && r.Tokens > r.Budget
+}
+
+// Measure renders the tools/list result the generated server returns for
+// data and estimates its size. Dynamic mode servers list meta-tools instead,
+// so this is then the cost of the operations they keep out of the context.
+func Measure(data *core.TemplateData, budget int) (*Report, error) {
+	report := &Report{Budget: budget}
+	definitions := make([]any, 0, len(data.Tools))
+	for _, tool := range data.Tools {
+		def := definition(tool)
+		raw, err := json.Marshal(def)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render tool %s: %v", tool.Name, err)
+		}
+		report.Tools = append(report.Tools, Tool{Name: tool.Name, Tokens: Tokens(string(raw)), Bytes: len(raw)})
+		definitions = append(definitions, def)
+	}
+	raw, err := json.Marshal(map[string]any{"tools": definitions})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the tool list: %v", err)
+	}
+	report.Tokens = Tokens(string(raw))
+	report.Bytes = len(raw)
+
+	if len(report.Tools) > 1 {
+		sum := 0
+		for _, tool := range report.Tools {
+			sum += tool.Tokens
+		}
+		for i := range report.Tools {
+			report.Tools[i].Expensive = report.Tools[i].Tokens*len(report.Tools) > 2*sum
+		}
+	}
+	sort.SliceStable(report.Tools, func(i, j int) bool {
+		return report.Tools[i].Tokens > report.Tools[j].Tokens
+	})
+	return report, nil
+}
+
+// definition is the MCP tool definition of tool, as listed by the
+// generated server.
+func definition(tool core.Tool) map[string]any {
+	definition := map[string]any{
+		"name":        tool.Name,
+		"description": tool.Description,
+		"inputSchema": tool.InputSchema,
+	}
+	if tool.OutputSchema != nil {
+		definition["outputSchema"] = tool.OutputSchema
+	}
+	annotations := make(map[string]any)
+	if tool.Annotations.Title != "" {
+		annotations["title"] = tool.Annotations.Title
+	}
+	for name, hint := range map[string]*bool{
+		"readOnlyHint":    tool.Annotations.ReadOnlyHint,
+		"destructiveHint": tool.Annotations.DestructiveHint,
+		"idempotentHint":  tool.Annotations.IdempotentHint,
+		"openWorldHint":   tool.Annotations.OpenWorldHint,
+	} {
+		if hint != nil {
+			annotations[name] = *hint
+		}
+	}
+	if len(annotations) > 0 {
+		definition["annotations"] = annotations
+	}
+	return definition
+}
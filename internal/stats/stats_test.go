@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+)
+
+func TestTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"   \n\t", 0},
+		{"pet", 1},
+		{"Find pet by ID", 4},
+		{"description", 2},
+		{"get_pet_by_petId", 4},
+		{"12345", 2},
+		{`{"type":"string"}`, 5},
+		{"宠物商店", 4},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Tokens(tt.text), tt.text)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	hint := true
+	small := func(name string) core.Tool {
+		return core.Tool{Name: name, Description: "Ping", InputSchema: map[string]any{"type": "object"}}
+	}
+	big := core.Tool{
+		Name:        "create_order",
+		Description: "Create an order for one or more items, with shipping and billing details",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"items":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Item identifiers"},
+				"shipping": map[string]any{"type": "string", "description": "Shipping address"},
+				"billing":  map[string]any{"type": "string", "description": "Billing address"},
+			},
+			"required": []string{"items"},
+		},
+		Annotations: core.ToolAnnotations{DestructiveHint: &hint},
+	}
+	data := &core.TemplateData{Tools: []core.Tool{small("ping_a"), big, small("ping_b"), small("ping_c")}}
+
+	report, err := Measure(data, 0)
+	require.NoError(t, err)
+	require.Len(t, report.Tools, 4)
+	assert.Equal(t, "create_order", report.Tools[0].Name)
+	assert.True(t, report.Tools[0].Expensive)
+	assert.False(t, report.Tools[1].Expensive)
+
+	sum := 0
+	for _, tool := range report.Tools {
+		sum += tool.Tokens
+	}
+	assert.GreaterOrEqual(t, report.Tokens, sum)
+	assert.False(t, report.OverBudget())
+
+	report, err = Measure(data, sum/2)
+	require.NoError(t, err)
+	assert.True(t, report.OverBudget())
+}
+
+func TestDefinition(t *testing.T) {
+	readOnly := true
+	def := definition(core.Tool{
+		Name:         "get_pet",
+		Description:  "Get a pet",
+		InputSchema:  map[string]any{"type": "object"},
+		OutputSchema: map[string]any{"type": "object"},
+		Annotations:  core.ToolAnnotations{Title: "Get pet", ReadOnlyHint: &readOnly},
+	})
+	assert.Equal(t, map[string]any{
+		"name":         "get_pet",
+		"description":  "Get a pet",
+		"inputSchema":  map[string]any{"type": "object"},
+		"outputSchema": map[string]any{"type": "object"},
+		"annotations":  map[string]any{"title": "Get pet", "readOnlyHint": true},
+	}, def)
+}
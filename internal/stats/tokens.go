@@ -0,0 +1,63 @@
+package stats
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tokens estimates how many tokens text takes up in a model's context. It
+// approximates the byte-pair encodings of current models without shipping
+// their vocabularies: words of up to six letters are single tokens and
+// longer ones split into six letter chunks, an underscore starts a new
+// word, digits group by three, runs of punctuation such as `":"` merge in
+// threes, whitespace attaches to the next token and other scripts such as
+// CJK cost a token per character.
+// Use it to compare tools and track growth rather than as an exact count.
+func Tokens(text string) int {
+	n := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || r == '_'):
+			j := i + 1
+			for j < len(text) && text[j] < utf8.RuneSelf && unicode.IsLetter(rune(text[j])) {
+				j++
+			}
+			n += ceilDiv(j-i, 6)
+			i = j
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(text) && text[j] >= '0' && text[j] <= '9' {
+				j++
+			}
+			if j == i {
+				// a non-ASCII digit
+				j = i + size
+			}
+			n += ceilDiv(j-i, 3)
+			i = j
+		case unicode.IsLetter(r):
+			n++
+			i += size
+		default:
+			j, runes := i, 0
+			for j < len(text) {
+				p, psize := utf8.DecodeRuneInString(text[j:])
+				if unicode.IsSpace(p) || unicode.IsLetter(p) || unicode.IsDigit(p) || p == '_' {
+					break
+				}
+				j += psize
+				runes++
+			}
+			n += ceilDiv(runes, 3)
+			i = j
+		}
+	}
+	return n
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/xxlv/ai-create-mcp/internal/adapters/oas/oas31"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/shared"
+	"github.com/xxlv/ai-create-mcp/internal/config"
+	"github.com/xxlv/ai-create-mcp/internal/lint"
+)
+
+// runLint implements `ai-create-mcp lint [-config file] [-json] <spec>`.
+func runLint(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Path to a TOML config file with a [lint] section")
+	asJSON := fs.Bool("json", false, "Print the findings as JSON")
+	var overlays stringList
+	fs.Var(&overlays, "overlay", "OpenAPI Overlay applied to the spec before linting, repeatable")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ai-create-mcp lint [-config file] [-overlay file]... [-json] <oas path>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 1
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		return 1
+	}
+	doc, err := oas31.New(fs.Arg(0), oas31.WithOverlays(overlays...)).Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error loading %s: %v\n", fs.Arg(0), err)
+		return 1
+	}
+	data, err := shared.Convert(doc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error converting %s: %v\n", fs.Arg(0), err)
+		return 1
+	}
+
+	findings := lint.Run(doc, data, cfg.Lint)
+	if *asJSON {
+		// a clean spec is an empty array, not null
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			return 1
+		}
+	} else {
+		printFindings(os.Stdout, findings)
+	}
+	for _, f := range findings {
+		if f.Severity == lint.Error {
+			return 1
+		}
+	}
+	return 0
+}
+
+func printFindings(w io.Writer, findings []lint.Finding) {
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "✅ No problems found")
+		return
+	}
+	counts := make(map[lint.Severity]int)
+	for _, f := range findings {
+		fmt.Fprintln(w, f)
+		counts[f.Severity]++
+	}
+	fmt.Fprintf(w, "\n%d error(s), %d warning(s), %d info\n", counts[lint.Error], counts[lint.Warning], counts[lint.Info])
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -10,8 +11,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
+	"reflect"
 	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -21,7 +25,12 @@ import (
 	"github.com/Masterminds/semver/v3"
 	"github.com/pelletier/go-toml"
 	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/merge"
 	"github.com/xxlv/ai-create-mcp/internal/adapters/oas/oas31"
+	"github.com/xxlv/ai-create-mcp/internal/config"
+	"github.com/xxlv/ai-create-mcp/internal/manifest"
+	"github.com/xxlv/ai-create-mcp/internal/pkgmgr"
+	"github.com/xxlv/ai-create-mcp/internal/stats"
 )
 
 //go:embed templates/__init__.py.tmpl
@@ -33,9 +42,10 @@ var serverTpl string
 //go:embed templates/README.md.tmpl
 var readmeTpl string
 
-const (
-	MinUVVersion = "0.4.10"
-)
+// templateVersion identifies the revision of the embedded templates. Bump it,
+// together with a new pkgmgr managed dependency set, whenever the generated
+// code needs different dependencies.
+const templateVersion = "5"
 
 type PyProject struct {
 	Data *toml.Tree
@@ -64,42 +74,6 @@ func (p *PyProject) FirstBinary() string {
 	return ""
 }
 
-func checkUVVersion(requiredVersion string) (string, error) {
-	cmd := exec.Command("uv", "--version")
-	output, err := cmd.Output()
-	if err != nil {
-		if _, ok := err.(*exec.Error); ok {
-			return "", nil // uv not found
-		}
-		return "", fmt.Errorf("failed to check uv version: %v", err)
-	}
-
-	version := strings.TrimSpace(string(output))
-	re := regexp.MustCompile(`uv (\d+\.\d+\.\d+)`)
-	matches := re.FindStringSubmatch(version)
-	if len(matches) < 2 {
-		return "", nil
-	}
-
-	versionNum := matches[1]
-	reqVer, _ := semver.NewVersion(requiredVersion)
-	curVer, _ := semver.NewVersion(versionNum)
-	if curVer.Compare(reqVer) >= 0 {
-		return version, nil
-	}
-	return "", nil
-}
-
-func ensureUVInstalled() error {
-	version, err := checkUVVersion(MinUVVersion)
-	if err != nil || version == "" {
-		fmt.Fprintf(os.Stderr, "❌ Error: uv >= %s is required but not installed.\n", MinUVVersion)
-		fmt.Fprintf(os.Stderr, "To install, visit: https://github.com/astral-sh/uv\n")
-		os.Exit(1)
-	}
-	return nil
-}
-
 func getClaudeConfigPath() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -127,7 +101,7 @@ func hasClaudeApp() bool {
 	return path != ""
 }
 
-func updateClaudeConfig(projectName, projectPath string) bool {
+func updateClaudeConfig(projectName, projectPath string, manager pkgmgr.Manager) bool {
 	configDir, err := getClaudeConfigPath()
 	if err != nil || configDir == "" {
 		return false
@@ -161,9 +135,13 @@ func updateClaudeConfig(projectName, projectPath string) bool {
 		return false
 	}
 
+	// Credentials stay in the project's dotenv file, out of the client
+	// configuration and of process listings
+	envFile := filepath.Join(projectPath, ".env")
+	runCmd := manager.RunCommand(projectPath, projectName)
 	mcpServers[projectName] = map[string]interface{}{
-		"command": "uv",
-		"args":    []string{"--directory", projectPath, "run", projectName},
+		"command": runCmd[0],
+		"args":    append(runCmd[1:], "--env-file", envFile),
 	}
 
 	updatedData, err := json.MarshalIndent(config, "", "  ")
@@ -179,6 +157,7 @@ func updateClaudeConfig(projectName, projectPath string) bool {
 
 	fmt.Printf("✅ Added %s to Claude.app configuration\n", projectName)
 	fmt.Printf("Settings file location: %s\n", configFile)
+	fmt.Printf("ℹ️ Put the server's credentials in %s, see its README\n", envFile)
 	return true
 }
 
@@ -198,7 +177,97 @@ func capitalizeBool(b bool) string {
 	return "False"
 }
 
-func copyTemplate(path, name, description, version string, adapter core.Adapter) error {
+// pyBool renders an optional boolean as a Python literal.
+func pyBool(b *bool) string {
+	if b == nil {
+		return "None"
+	}
+	return capitalizeBool(*b)
+}
+
+// pyJSON renders v, after a round trip through JSON, as a Python literal.
+func pyJSON(v any) (string, error) {
+	if v == nil || reflect.ValueOf(v).IsZero() {
+		return "None", nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	writePyLiteral(&b, value)
+	return b.String(), nil
+}
+
+func writePyLiteral(b *strings.Builder, value any) {
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("None")
+	case bool:
+		b.WriteString(capitalizeBool(v))
+	case json.Number:
+		b.WriteString(v.String())
+	case string:
+		// Go escapes are a subset of Python's
+		b.WriteString(strconv.QuoteToASCII(v))
+	case []any:
+		b.WriteString("[")
+		for i, item := range v {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writePyLiteral(b, item)
+		}
+		b.WriteString("]")
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("{")
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(strconv.QuoteToASCII(k))
+			b.WriteString(": ")
+			writePyLiteral(b, v[k])
+		}
+		b.WriteString("}")
+	}
+}
+
+// projectOptions are the user choices that shape a generated project.
+type projectOptions struct {
+	Name        string
+	Description string
+	Version     string
+	UseClaude   bool
+	Offline     bool
+	ToolMode    string // core.ToolModeStatic when empty
+	// Config carries the per-tool overrides applied after conversion.
+	Config *config.Config
+	// ConfigPath is the file Config was loaded from, recorded in the
+	// manifest; empty without one.
+	ConfigPath string
+}
+
+// generatorVersion reports the module version ai-create-mcp was built from.
+func generatorVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+func copyTemplate(path string, opts projectOptions, adapter core.Adapter, manager pkgmgr.Manager) error {
 	targetDir, err := getPackageDirectory(path)
 	if err != nil {
 		return err
@@ -210,6 +279,39 @@ func copyTemplate(path, name, description, version string, adapter core.Adapter)
 	if templateVars == nil {
 		return fmt.Errorf("failed to convert oas as templates, please check your oas path")
 	}
+	templateVars.MaxBinarySize = core.DefaultMaxBinarySize
+	templateVars.MaxResponseSize = core.DefaultMaxResponseSize
+	templateVars.Timeout = core.DefaultTimeout
+	templateVars.Retries = core.DefaultRetries
+	templateVars.RetryBackoff = core.DefaultRetryBackoff
+	if opts.Config != nil {
+		warnings, err := opts.Config.Apply(templateVars)
+		if err != nil {
+			return err
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "WARN: %s\n", warning)
+		}
+	}
+	templateVars.ToolMode = opts.ToolMode
+	if templateVars.ToolMode == "" {
+		templateVars.ToolMode = core.ToolModeStatic
+	}
+	// dynamic servers do not list the operations, so they never exceed it
+	if opts.Config != nil && opts.Config.TokenBudget > 0 && templateVars.ToolMode == core.ToolModeStatic {
+		report, err := stats.Measure(templateVars, opts.Config.TokenBudget)
+		if err != nil {
+			return err
+		}
+		if report.OverBudget() {
+			return fmt.Errorf("the tool list takes about %d tokens, over the token_budget of %d; run `ai-create-mcp stats` to find the most expensive tools, or use -tool-mode dynamic", report.Tokens, report.Budget)
+		}
+	}
+	templateVars.BinaryName = opts.Name
+	templateVars.ServerDescription = opts.Description
+	templateVars.ServerDirectory = filepath.Base(path)
+	templateVars.InstallCommand = manager.SyncCommand()
+	templateVars.RunCommand = strings.Join(manager.RunCommand(".", opts.Name), " ")
 	templates := []struct {
 		name      string
 		content   string
@@ -232,9 +334,35 @@ func copyTemplate(path, name, description, version string, adapter core.Adapter)
 		},
 	}
 
+	m := &manifest.Manifest{
+		GeneratorVersion: generatorVersion(),
+		TemplateVersion:  templateVersion,
+		Options: manifest.Options{
+			Name:           opts.Name,
+			Description:    opts.Description,
+			Version:        opts.Version,
+			PackageManager: manager.Name(),
+			Offline:        opts.Offline,
+			ToolMode:       templateVars.ToolMode,
+		},
+	}
+	for _, src := range templateVars.Sources {
+		m.Specs = append(m.Specs, manifest.Spec{Type: src.Type, Location: src.Location, SHA256: src.Digest})
+	}
+	if opts.ConfigPath != "" {
+		data, err := os.ReadFile(opts.ConfigPath)
+		if err != nil {
+			return fmt.Errorf("failed to read config %s: %v", opts.ConfigPath, err)
+		}
+		m.Options.Config = opts.ConfigPath
+		m.Options.ConfigSHA256 = manifest.Hash(data)
+	}
+
 	for _, t := range templates {
 		tmpl := template.New(t.name).Funcs(template.FuncMap{
 			"capitalizeBool": capitalizeBool,
+			"pyBool":         pyBool,
+			"pyJSON":         pyJSON,
 		})
 
 		tmpl, err := tmpl.Parse(t.content) // In practice, load from file or embed
@@ -242,19 +370,23 @@ func copyTemplate(path, name, description, version string, adapter core.Adapter)
 			return fmt.Errorf("failed to parse template %s: %v", t.name, err)
 		}
 
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, templateVars); err != nil {
+			return fmt.Errorf("failed to render template %s: %v", t.name, err)
+		}
+
 		outPath := filepath.Join(t.outputDir, t.name)
-		file, err := os.Create(outPath)
-		if err != nil {
+		if err := os.WriteFile(outPath, buf.Bytes(), 0644); err != nil {
 			return fmt.Errorf("failed to create file %s: %v", outPath, err)
 		}
-		defer file.Close()
-
-		if err := tmpl.Execute(file, templateVars); err != nil {
-			return fmt.Errorf("failed to render template %s: %v", t.name, err)
+		rel, err := filepath.Rel(path, outPath)
+		if err != nil {
+			return err
 		}
+		m.AddFile(rel, buf.Bytes())
 	}
 
-	return nil
+	return m.Write(path)
 }
 
 func checkPackageName(name string) bool {
@@ -281,61 +413,77 @@ func checkPackageName(name string) bool {
 	return true
 }
 
-func createProject(path, name, description, version string, adapter core.Adapter, useClaude bool) error {
+func createProject(path string, opts projectOptions, adapter core.Adapter, manager pkgmgr.Manager) error {
 	if err := os.MkdirAll(path, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %v", err)
 	}
 
-	cmd := exec.Command("uv", "init", "--name", name, "--package", "--app", "--quiet")
-	cmd.Dir = path
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to initialize project: %v", err)
+	if err := manager.Init(path, opts.Name); err != nil {
+		return err
 	}
 
-	cmd = exec.Command("uv", "add", "mcp")
-	cmd.Dir = path
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to add mcp dependency: %v", err)
+	deps, err := pkgmgr.Managed(templateVersion)
+	if err != nil {
+		return err
 	}
-	// TODO: managed ?
-	cmd = exec.Command("uv", "add", "aiohttp")
-	cmd.Dir = path
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to add aiohttp dependency: %v", err)
+	if opts.Offline {
+		err = pkgmgr.WriteDependencies(path, deps)
+	} else {
+		err = manager.Add(path, pkgmgr.Requirements(deps)...)
+	}
+	if err != nil {
+		return err
+	}
+	if err := recordTemplateVersion(path, templateVersion); err != nil {
+		return err
 	}
 
-	if err := copyTemplate(path, name, description, version, adapter); err != nil {
+	if err := copyTemplate(path, opts, adapter, manager); err != nil {
 		return fmt.Errorf("failed to copy templates: %v", err)
 	}
 
-	if useClaude && hasClaudeApp() {
+	if opts.UseClaude && hasClaudeApp() {
 		reader := bufio.NewReader(os.Stdin)
 		fmt.Print("\nClaude.app detected. Would you like to install the server into Claude.app now? [Y/n]: ")
 		response, _ := reader.ReadString('\n')
 		if strings.TrimSpace(strings.ToLower(response)) != "n" {
-			updateClaudeConfig(name, path)
+			updateClaudeConfig(opts.Name, path, manager)
 		}
 	}
 	basePath, _ := os.Getwd()
 	relPath, _ := filepath.Rel(basePath, path)
-	fmt.Printf("✅ Created project %s in %s\n", name, relPath)
+	fmt.Printf("✅ Created project %s in %s\n", opts.Name, relPath)
 	fmt.Printf("ℹ️ To install dependencies run:\n")
 	fmt.Printf("   cd %s\n", relPath)
-	fmt.Println("   uv sync --dev --all-extras")
-	return compileDep(relPath)
+	fmt.Printf("   %s\n", manager.SyncCommand())
+	if opts.Offline {
+		fmt.Println("ℹ️ Offline mode: dependencies were written to pyproject.toml but not installed.")
+		return nil
+	}
+	return compileDep(path, manager)
 }
 
-func compileDep(workspacePath string) error {
+func compileDep(workspacePath string, manager pkgmgr.Manager) error {
 	fmt.Printf("ℹ️ Installing dependencies...\n")
-	cmd := exec.Command("uv", "sync", "--dev", "--all-extras")
-	cmd.Dir = workspacePath
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	return manager.Sync(workspacePath)
+}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to sync dependencies: %v", err)
+// recordTemplateVersion stores the template revision under
+// [tool.ai-create-mcp] so the pinned dependency set can be traced back later.
+func recordTemplateVersion(projectPath, templateVersion string) error {
+	pyprojectPath := filepath.Join(projectPath, "pyproject.toml")
+	data, err := toml.LoadFile(pyprojectPath)
+	if err != nil {
+		return fmt.Errorf("pyproject.toml not found: %v", err)
 	}
-	return nil
+	data.Set("tool.ai-create-mcp.template-version", templateVersion)
+
+	file, err := os.Create(pyprojectPath)
+	if err != nil {
+		return fmt.Errorf("failed to open pyproject.toml: %v", err)
+	}
+	defer file.Close()
+	return toml.NewEncoder(file).Encode(data)
 }
 
 func updatePyProjectSettings(projectPath, version, description string) error {
@@ -379,8 +527,9 @@ func openBrowser(url string) error {
 }
 
 // runInspector use mcp inspcector package
-func runInspector(projectPath, projectName string) error {
-	cmd := exec.Command("npx", "@modelcontextprotocol/inspector", "uv", "--directory", fmt.Sprintf(`"%s"`, projectPath), "run", projectName)
+func runInspector(projectPath, projectName string, manager pkgmgr.Manager) error {
+	args := append([]string{"@modelcontextprotocol/inspector"}, manager.RunCommand(projectPath, projectName)...)
+	cmd := exec.Command("npx", args...)
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = os.Stdout
 
@@ -422,33 +571,124 @@ func generateRandomLowString(length int) string {
 	return string(b)
 }
 
+// stringList is a flag that may be given several times, keeping every value
+// in order.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// buildAdapter converts a single spec directly and merges several into one
+// server, each routed to its own upstream. It returns nil without specs.
+func buildAdapter(oasPaths, overlays []string, sources []config.SourceConfig) core.Adapter {
+	if len(oasPaths) == 1 && len(sources) == 0 {
+		return oas31.New(oasPaths[0], oas31.WithOverlays(overlays...))
+	}
+	var merged []merge.Source
+	for _, oasPath := range oasPaths {
+		merged = append(merged, merge.Source{Adapter: oas31.New(oasPath, oas31.WithOverlays(overlays...))})
+	}
+	for _, src := range sources {
+		m := merge.Source{
+			Name:    src.Name,
+			Prefix:  src.Prefix,
+			BaseURL: src.BaseURL,
+			Adapter: oas31.New(src.Spec, oas31.WithOverlays(src.Overlays...)),
+		}
+		if src.Auth != nil {
+			m.Auth = &core.Auth{Type: src.Auth.Type, Name: src.Auth.Name, Env: src.Auth.Env, TokenCommand: src.Auth.TokenCommand}
+		}
+		if src.RateLimit != nil {
+			limit := src.RateLimit.Limit()
+			m.RateLimit = &limit
+		}
+		merged = append(merged, m)
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merge.New(merged...)
+}
+
+// commands are the subcommands dispatched on the first argument. Without one
+// ai-create-mcp generates a project.
+var commands = map[string]func(args []string) int{
+	"check": runCheck,
+	"diff":  runDiff,
+	"lint":  runLint,
+	"stats": runStats,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			os.Exit(cmd(os.Args[2:]))
+		}
+	}
+
 	var (
 		path        string
 		name        string
-		oasPath     string
 		version     string
 		description string
 		claudeApp   bool
 		inspector   bool
 		y           bool
+		configPath  string
+		managerName string
+		offline     bool
+		toolMode    string
+		overlays    stringList
+		oasPaths    stringList
 	)
 
 	flag.StringVar(&path, "path", "", "Directory to create project in")
 	flag.StringVar(&name, "name", "", "Project name")
-	flag.StringVar(&oasPath, "oaspath", "", "Oas path,if set ignore postman's config")
+	flag.Var(&oasPaths, "oaspath", "Oas path, repeatable to merge several specs into one server")
 	flag.StringVar(&version, "version", "0.1.0", "Server version")
 	flag.BoolVar(&inspector, "inspector", false, "Open inspector")
 	flag.StringVar(&description, "description", "Simple mcp", "Project description")
 	flag.BoolVar(&claudeApp, "claudeapp", true, "Enable/disable Claude.app integration")
 	flag.BoolVar(&y, "autoyes", true, "Enable/disable auto yes")
+	flag.StringVar(&configPath, "config", "", "Path to a TOML config file")
+	flag.StringVar(&managerName, "manager", "", fmt.Sprintf("Python package manager (%s), default %s", strings.Join(pkgmgr.Names(), ", "), pkgmgr.Default))
+
+	flag.BoolVar(&offline, "offline", false, "Write pinned dependencies to pyproject.toml without installing them")
+	flag.StringVar(&toolMode, "tool-mode", "", fmt.Sprintf("Expose every operation as a tool (%s) or search, describe and invoke meta-tools (%s), default %s", core.ToolModeStatic, core.ToolModeDynamic, core.ToolModeStatic))
+	flag.Var(&overlays, "overlay", "OpenAPI Overlay applied to the spec before conversion, repeatable and applied in order")
 
 	flag.Parse()
 
-	if err := ensureUVInstalled(); err != nil {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	if managerName == "" {
+		managerName = cfg.PackageManager
+	}
+	offline = offline || cfg.Offline
+	if toolMode == "" {
+		toolMode = cfg.ToolMode
+	}
+	if toolMode != "" && !core.ValidToolMode(toolMode) {
+		fmt.Fprintf(os.Stderr, "❌ Error: -tool-mode must be %s or %s\n", core.ToolModeStatic, core.ToolModeDynamic)
 		os.Exit(1)
 	}
-	fmt.Println("Creating a new MCP server project using uv.")
+	manager, err := pkgmgr.New(managerName, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := manager.EnsureInstalled(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Creating a new MCP server project using %s.\n", manager.Name())
 	fmt.Println("This will set up a Python project with MCP dependency.")
 	fmt.Println("\nLet's begin!")
 
@@ -484,16 +724,20 @@ func main() {
 			version = "0.1.0"
 		}
 	}
-	var adapter core.Adapter
-	if oasPath == "" {
+	if len(oasPaths) == 0 && len(cfg.Sources) == 0 {
 		fmt.Print("Oas path(required): ")
-		oasPath, _ = reader.ReadString('\n')
-		oasPath = strings.TrimSpace(oasPath)
+		oasPath, _ := reader.ReadString('\n')
+		if oasPath = strings.TrimSpace(oasPath); oasPath != "" {
+			oasPaths = append(oasPaths, oasPath)
+		}
 	}
-
-	if oasPath != "" {
-		adapter = oas31.New(oasPath)
+	// an overlay is written against one spec, and would match nothing or the
+	// wrong operations in the others
+	if len(oasPaths) > 1 && len(overlays) > 0 {
+		fmt.Fprintln(os.Stderr, "❌ Error: -overlay applies to a single -oaspath; give each spec its overlays with [[sources]] in the config file")
+		os.Exit(1)
 	}
+	adapter := buildAdapter(oasPaths, overlays, cfg.Sources)
 	if adapter == nil {
 		fmt.Fprintln(os.Stderr, "❌ Error: Please use `-oaspath` to specify the path of the oas file")
 		os.Exit(1)
@@ -523,7 +767,17 @@ func main() {
 	}
 
 	projectPath = filepath.Clean(projectPath)
-	if err := createProject(projectPath, name, description, version, adapter, claudeApp); err != nil {
+	opts := projectOptions{
+		Name:        name,
+		Description: description,
+		Version:     version,
+		UseClaude:   claudeApp,
+		Offline:     offline,
+		ToolMode:    toolMode,
+		Config:      cfg,
+		ConfigPath:  configPath,
+	}
+	if err := createProject(projectPath, opts, adapter, manager); err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -534,7 +788,7 @@ func main() {
 	}
 
 	if inspector {
-		if err := runInspector(projectPath, name); err != nil {
+		if err := runInspector(projectPath, name, manager); err != nil {
 			fmt.Fprintf(os.Stderr, "❌ Error running inspector: %v\n", err)
 			os.Exit(1)
 		}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/core"
+	"github.com/xxlv/ai-create-mcp/internal/adapters/oas/oas31"
+	"github.com/xxlv/ai-create-mcp/internal/config"
+	"github.com/xxlv/ai-create-mcp/internal/manifest"
+	"github.com/xxlv/ai-create-mcp/internal/pkgmgr"
+)
+
+func testOptions(offline bool) projectOptions {
+	return projectOptions{Name: "petstore", Description: "Petstore tools", Version: "0.1.0", Offline: offline}
+}
+
+func TestCreateProject(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "petstore")
+	manager := &pkgmgr.Fake{}
+
+	err := createProject(dir, testOptions(false), oas31.New("testdata/openapi.yml"), manager)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"init petstore", "add mcp>=1.10.0,<2.0.0 aiohttp>=3.9.0,<4.0.0 jsonschema[format-nongpl]>=4.20.0,<5.0.0", "sync"}, manager.Calls)
+	for _, file := range []string{"README.md", "src/petstore/__init__.py", "src/petstore/server.py"} {
+		assert.FileExists(t, filepath.Join(dir, file))
+	}
+
+	readme, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(readme), "fake sync")
+
+	server, err := os.ReadFile(filepath.Join(dir, "src/petstore/server.py"))
+	require.NoError(t, err)
+	// GET operations with path parameters are resource templates
+	assert.Contains(t, string(server), `uriTemplate="ai-create-mcp://internal/pet_{petId}"`)
+	assert.NotContains(t, string(server), `uri=AnyUrl("ai-create-mcp://internal/pet_{petId}")`)
+
+	m, err := manifest.Read(dir)
+	require.NoError(t, err)
+	assert.Equal(t, templateVersion, m.TemplateVersion)
+	assert.Equal(t, "fake", m.Options.PackageManager)
+	assert.Equal(t, core.ToolModeStatic, m.Options.ToolMode)
+	require.Len(t, m.Specs, 1)
+	assert.Equal(t, "testdata/openapi.yml", m.Specs[0].Location)
+	spec, err := os.ReadFile("testdata/openapi.yml")
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Hash(spec), m.Specs[0].SHA256)
+	assert.Equal(t, manifest.Hash(readme), m.Files["README.md"])
+	assert.Contains(t, m.Files, "src/petstore/server.py")
+
+	drift, err := m.Drift(dir)
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+}
+
+func TestCreateProjectConfigManifest(t *testing.T) {
+	cfg := filepath.Join(t.TempDir(), "config.toml")
+	content := []byte("fields_argument = true\n")
+	require.NoError(t, os.WriteFile(cfg, content, 0644))
+	opts := testOptions(true)
+	opts.Config = &config.Config{FieldsArgument: true}
+	opts.ConfigPath = cfg
+	dir := filepath.Join(t.TempDir(), "petstore")
+	require.NoError(t, createProject(dir, opts, oas31.New("testdata/openapi.yml"), &pkgmgr.Fake{}))
+
+	m, err := manifest.Read(dir)
+	require.NoError(t, err)
+	assert.Equal(t, cfg, m.Options.Config)
+	assert.Equal(t, manifest.Hash(content), m.Options.ConfigSHA256)
+	inputs, err := m.ChangedInputs()
+	require.NoError(t, err)
+	assert.Empty(t, inputs)
+}
+
+func TestCreateProjectOffline(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "petstore")
+	manager := &pkgmgr.Fake{}
+
+	err := createProject(dir, testOptions(true), oas31.New("testdata/openapi.yml"), manager)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"init petstore"}, manager.Calls)
+	pyproject, err := toml.LoadFile(filepath.Join(dir, "pyproject.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"mcp>=1.10.0,<2.0.0", "aiohttp>=3.9.0,<4.0.0", "jsonschema[format-nongpl]>=4.20.0,<5.0.0"}, pyproject.Get("project.dependencies"))
+	assert.Equal(t, templateVersion, pyproject.Get("tool.ai-create-mcp.template-version"))
+}
+
+func TestCreateProjectDynamicTools(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "petstore")
+	opts := testOptions(true)
+	opts.ToolMode = core.ToolModeDynamic
+
+	err := createProject(dir, opts, oas31.New("testdata/openapi.yml"), &pkgmgr.Fake{})
+	require.NoError(t, err)
+
+	server, err := os.ReadFile(filepath.Join(dir, "src/petstore/server.py"))
+	require.NoError(t, err)
+	for _, tool := range []string{"search_operations", "describe_operation", "invoke_operation"} {
+		assert.Contains(t, string(server), `name="`+tool+`"`)
+	}
+	// operations are only reachable through invoke_operation
+	assert.Equal(t, 3, strings.Count(string(server), "inputSchema="))
+	assert.Contains(t, string(server), `"get_pet_by_petId": {`)
+
+	m, err := manifest.Read(dir)
+	require.NoError(t, err)
+	assert.Equal(t, core.ToolModeDynamic, m.Options.ToolMode)
+}
+
+func TestCreateProjectTokenBudget(t *testing.T) {
+	opts := testOptions(true)
+	opts.Config = &config.Config{TokenBudget: 500}
+
+	err := createProject(filepath.Join(t.TempDir(), "petstore"), opts, oas31.New("testdata/openapi.yml"), &pkgmgr.Fake{})
+	assert.ErrorContains(t, err, "over the token_budget of 500")
+
+	// dynamic servers only list the meta-tools
+	opts.ToolMode = core.ToolModeDynamic
+	err = createProject(filepath.Join(t.TempDir(), "petstore"), opts, oas31.New("testdata/openapi.yml"), &pkgmgr.Fake{})
+	assert.NoError(t, err)
+}
+
+func TestUpdateClaudeConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GOOS", "darwin")
+	configDir := filepath.Join(home, "Library", "Application Support", "Claude")
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	configFile := filepath.Join(configDir, "claude_desktop_config.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{}`), 0644))
+
+	require.True(t, updateClaudeConfig("petstore", "/work/petstore", &pkgmgr.Fake{}))
+	data, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	// credentials come from the project's dotenv file, not from the entry
+	assert.JSONEq(t, `{"mcpServers": {"petstore": {
+		"command": "fake",
+		"args": ["run", "petstore", "--env-file", "/work/petstore/.env"]
+	}}}`, string(data))
+}
+
+func TestCreateProjectQuotedConfig(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 is not installed")
+	}
+	opts := testOptions(true)
+	opts.Config = &config.Config{
+		Tools: map[string]config.ToolConfig{
+			"get_pet_by_petId": {Annotations: config.Annotations{Title: `Find the "pet" \ """`}},
+		},
+		Prompts: map[string]config.PromptConfig{
+			"get_pet_by_petId": {Description: `Look up a "pet" \ """`},
+		},
+	}
+	dir := filepath.Join(t.TempDir(), "petstore")
+	require.NoError(t, createProject(dir, opts, oas31.New("testdata/openapi.yml"), &pkgmgr.Fake{}))
+
+	// strings of the config end up in Python literals
+	out, err := exec.Command(python, "-m", "py_compile", filepath.Join(dir, "src/petstore/server.py")).CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestCreateProjectQuotedSpec(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 is not installed")
+	}
+	spec := filepath.Join(t.TempDir(), "openapi.yml")
+	require.NoError(t, os.WriteFile(spec, []byte(`
+openapi: 3.0.3
+info:
+  title: Quotes
+  version: 1.0.0
+servers:
+  - url: 'https://api.example.com/"v1"'
+paths:
+  '/notes/{id}/"raw"\':
+    get:
+      summary: 'Read a note \'
+      x-mcp-resource: true
+      x-mcp-prompt: {description: 'Summarize a note \'}
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+        - {name: 'fil"ter\', in: query, description: 'Filter \', schema: {type: string}}
+      responses:
+        '200':
+          description: OK
+          content:
+            'application/vnd."note"+json': {schema: {type: object}}
+`), 0o644))
+
+	for _, mode := range []string{core.ToolModeStatic, core.ToolModeDynamic} {
+		opts := testOptions(true)
+		opts.ToolMode = mode
+		dir := filepath.Join(t.TempDir(), "petstore")
+		require.NoError(t, createProject(dir, opts, oas31.New(spec), &pkgmgr.Fake{}))
+
+		// strings of the spec end up in Python literals
+		out, err := exec.Command(python, "-m", "py_compile", filepath.Join(dir, "src/petstore/server.py")).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+}
+
+func TestServerChecks(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 is not installed")
+	}
+	dir := filepath.Join(t.TempDir(), "petstore")
+	require.NoError(t, createProject(dir, testOptions(true), oas31.New("testdata/openapi.yml"), &pkgmgr.Fake{}))
+
+	for _, script := range []string{"testdata/check_urls.py", "testdata/check_redaction.py"} {
+		t.Run(filepath.Base(script), func(t *testing.T) {
+			out, err := exec.Command(python, "-B", script, filepath.Join(dir, "src/petstore/server.py")).CombinedOutput()
+			require.NoError(t, err, string(out))
+			assert.Equal(t, "ok\n", string(out))
+		})
+	}
+}
+
+func TestPyJSON(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{nil, "None"},
+		{map[string]any(nil), "None"},
+		{map[string]any{"type": "object", "required": []string{"id"}, "nullable": true, "default": nil}, `{"default": None, "nullable": True, "required": ["id"], "type": "object"}`},
+		{map[string]any{"maximum": 1e20, "minimum": -1.5, "count": 3}, `{"count": 3, "maximum": 100000000000000000000, "minimum": -1.5}`},
+		{[]any{"quote \" and \\ and \n", "naïve"}, `["quote \" and \\ and \n", "na\u00efve"]`},
+	}
+	for _, tt := range tests {
+		got, err := pyJSON(tt.value)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/xxlv/ai-create-mcp/internal/config"
+	"github.com/xxlv/ai-create-mcp/internal/stats"
+)
+
+// runStats implements `ai-create-mcp stats [-config file] [-budget n] [-json] <spec>...`.
+func runStats(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Path to a TOML config file")
+	budget := fs.Int("budget", 0, "Fail when the tool list exceeds this many tokens, defaults to token_budget of the config file")
+	top := fs.Int("top", 10, "Number of tools listed, most expensive first; 0 lists all")
+	asJSON := fs.Bool("json", false, "Print the report as JSON")
+	var overlays stringList
+	fs.Var(&overlays, "overlay", "OpenAPI Overlay applied to the spec before conversion, repeatable")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ai-create-mcp stats [-config file] [-overlay file]... [-budget n] [-top n] [-json] [oas path]...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		return 1
+	}
+	if *budget < 0 {
+		fmt.Fprintln(os.Stderr, "❌ Error: -budget must not be negative")
+		return 1
+	}
+	if *budget == 0 {
+		*budget = cfg.TokenBudget
+	}
+	adapter := buildAdapter(fs.Args(), overlays, cfg.Sources)
+	if adapter == nil {
+		fs.Usage()
+		return 1
+	}
+	data, err := adapter.ToTemplateData()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error converting: %v\n", err)
+		return 1
+	}
+	// measure the tools as generated, with the config overrides applied
+	warnings, err := cfg.Apply(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		return 1
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "WARN: %s\n", warning)
+	}
+
+	report, err := stats.Measure(data, *budget)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		return 1
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			return 1
+		}
+	} else {
+		printStats(os.Stdout, report, *top)
+	}
+	if report.OverBudget() {
+		return 1
+	}
+	return 0
+}
+
+func printStats(w io.Writer, report *stats.Report, top int) {
+	if len(report.Tools) == 0 {
+		fmt.Fprintln(w, "No tools generated")
+		return
+	}
+	tools := report.Tools
+	if top > 0 && top < len(tools) {
+		tools = tools[:top]
+	}
+	fmt.Fprintf(w, "%8s %6s  %s\n", "TOKENS", "SHARE", "TOOL")
+	for _, tool := range tools {
+		mark := ""
+		if tool.Expensive {
+			mark = "  ⚠ more than twice the average"
+		}
+		fmt.Fprintf(w, "%8d %5.1f%%  %s%s\n", tool.Tokens, 100*float64(tool.Tokens)/float64(report.Tokens), tool.Name, mark)
+	}
+	if len(tools) < len(report.Tools) {
+		fmt.Fprintf(w, "%8s %6s  ... %d more\n", "", "", len(report.Tools)-len(tools))
+	}
+	fmt.Fprintf(w, "\n%d tools, about %d tokens (%d bytes) in tools/list\n", len(report.Tools), report.Tokens, report.Bytes)
+	switch {
+	case report.OverBudget():
+		fmt.Fprintf(w, "❌ Over the budget of %d tokens by %d\n", report.Budget, report.Tokens-report.Budget)
+	case report.Budget > 0:
+		fmt.Fprintf(w, "✅ Within the budget of %d tokens\n", report.Budget)
+	}
+}
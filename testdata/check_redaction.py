@@ -0,0 +1,82 @@
+"""Runs the redaction helpers of a generated server.py against credentials.
+
+Usage: python3 check_redaction.py path/to/server.py
+
+The server's third-party imports are replaced with stand-ins by stubbed.py,
+so only the standard library is needed. Exits non-zero on the first failed
+check.
+"""
+import json
+import sys
+import urllib.parse
+
+from stubbed import load_server
+
+server = load_server(sys.argv[1])
+REDACTED = server.REDACTED
+TOKEN = "s3cr3t-t0ken"
+
+name, upstream = next(iter(server.UPSTREAMS.items()))
+server.CREDENTIALS[name] = TOKEN
+# an API key sent in a header or query parameter of its own
+keyed = {**upstream, "auth": {**upstream["auth"], "type": "header", "name": "X-Pet-Key"}}
+
+# secret names are compared lowercased
+assert all(secret == secret.lower() for secret in server.SECRET_NAMES)
+
+# headers
+headers = {
+    "Authorization": f"Bearer {TOKEN}",
+    "Proxy-Authorization": "Basic dTpw",
+    "Cookie": "session=abc",
+    "X-API-Key": "k1",
+    "Accept": "application/json",
+}
+assert server.redact_headers(headers, upstream) == {
+    "Authorization": REDACTED,
+    "Proxy-Authorization": REDACTED,
+    "Cookie": REDACTED,
+    "X-API-Key": REDACTED,
+    "Accept": "application/json",
+}
+assert server.redact_headers({"x-pet-key": TOKEN, "X-Request-ID": "1"}, keyed) == {"x-pet-key": REDACTED, "X-Request-ID": "1"}
+
+# query strings
+url = "https://api.example.com/pet/findByStatus"
+logged = server.logged_url(url, [("status", "sold"), ("api_key", "k1"), ("Access_Token", "k2"), ("X-Pet-Key", TOKEN), ("tags[]", "a b")], keyed)
+path, _, query = logged.partition("?")
+assert path == url
+assert urllib.parse.parse_qsl(query) == [
+    ("status", "sold"),
+    ("api_key", REDACTED),
+    ("Access_Token", REDACTED),
+    ("X-Pet-Key", REDACTED),
+    ("tags[]", "a b"),
+]
+assert TOKEN not in logged
+assert server.logged_url(url, [], upstream) == url
+
+# JSON bodies, secret fields at any depth
+body = {
+    "user": {"name": "ann", "Password": "pw", "sessions": [{"refresh_token": "r1", "device": "phone"}]},
+    "client_secret": "c1",
+    "token": None,
+}
+redacted = {
+    "user": {"name": "ann", "Password": REDACTED, "sessions": [{"refresh_token": REDACTED, "device": "phone"}]},
+    "client_secret": REDACTED,
+    "token": REDACTED,
+}
+assert json.loads(server.redact_body(json.dumps(body))) == redacted
+assert json.loads(server.logged_body(json.dumps(body))) == redacted
+assert json.loads(server.logged_body(body)) == redacted
+assert server.redact_body("password=pw") == "password=pw"
+assert server.logged_body(b"\x00\x01\x02") == "<3 bytes>"
+assert server.logged_body(None) is None
+assert server.redact_value({"file": server.Upload("a.png", b"1234")}) == {"file": "<4 bytes>"}
+
+# credentials of the server anywhere in text
+assert server.redact_text(f"GET {url}?key={TOKEN} failed: bad token {TOKEN}") == f"GET {url}?key={REDACTED} failed: bad token {REDACTED}"
+server.CREDENTIALS[name] = None
+assert server.redact_text("nothing to hide") == "nothing to hide"
+print("ok")
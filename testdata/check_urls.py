@@ -0,0 +1,122 @@
+"""Runs the URL helpers of a generated server.py against hostile arguments.
+
+Usage: python3 check_urls.py path/to/server.py
+
+The server's third-party imports are replaced with stand-ins by stubbed.py,
+so only the standard library is needed. Exits non-zero on the first failed
+check.
+"""
+import asyncio
+import contextlib
+import sys
+
+from stubbed import load_server
+
+# the request URL passes through yarl.URL as it is
+sys.modules["yarl"].URL = lambda url, encoded=False: url
+
+server = load_server(sys.argv[1])
+
+BASE = next(u for upstream in server.UPSTREAMS.values() for u in upstream["base_urls"])
+
+
+def request_url(template, **arguments):
+    path, _ = server.eat(template, arguments)
+    url = BASE.rstrip("/") + path
+    server.check_url(url, BASE)
+    return url
+
+
+def refused(fn, *args):
+    try:
+        fn(*args)
+    except ValueError:
+        return True
+    return False
+
+
+# path arguments stay one segment of the operation's path
+assert request_url("/user/{username}", username="../../admin") == BASE + "/user/..%2F..%2Fadmin"
+assert request_url("/user/{username}", username="..") == BASE + "/user/%2E%2E"
+assert request_url("/user/{username}", username="%2e%2e") == BASE + "/user/%252e%252e"
+assert request_url("/user/{username}", username="a/b?x=1#f") == BASE + "/user/a%2Fb%3Fx%3D1%23f"
+assert request_url("/user/{username}", username="https://evil.example/x") == BASE + "/user/https%3A%2F%2Fevil.example%2Fx"
+assert request_url("/user/{username}", username="//evil.example") == BASE + "/user/%2F%2Fevil.example"
+
+# URLs leaving the base URL are refused
+assert refused(server.check_url, "https://evil.example/api/v3/pet", BASE)
+assert refused(server.check_url, BASE + "/../admin", BASE)
+assert refused(server.check_url, BASE + "/./pet", BASE)
+assert refused(server.check_url, BASE.rsplit("/", 1)[0] + "/v30/pet", BASE)
+assert refused(server.check_url, BASE.replace("https://", "http://") + "/pet", BASE)
+assert not refused(server.check_url, BASE + "/pet/1", BASE)
+
+# wildcard patterns match subdomains only
+server.ALLOWED_HOSTS = ["*.example.com"]
+assert server.host_allowed("api.example.com")
+assert server.host_allowed("a.b.example.com")
+assert server.host_allowed("API.Example.com")
+assert not server.host_allowed("example.com")
+assert not server.host_allowed("evilexample.com")
+assert not server.host_allowed("example.com.evil.net")
+assert not server.host_allowed(None)
+assert refused(server.check_url, BASE + "/pet", BASE)
+server.ALLOWED_HOSTS = []
+
+
+class Response:
+    def __init__(self, status, location=None):
+        self.status = status
+        self.headers = {"Location": location} if location else {}
+
+
+class Session:
+    """Answers requests with canned responses, recording them."""
+
+    def __init__(self, *responses):
+        self.responses = list(responses)
+        self.requests = []
+
+    @contextlib.asynccontextmanager
+    async def request(self, method, url, **kwargs):
+        self.requests.append((method, url, "json" in kwargs))
+        yield self.responses.pop(0)
+
+
+async def follow(session, method, url, **kwargs):
+    server.get_session = lambda: session
+    async with server.send_request(method, url, **kwargs) as response:
+        return response.status
+
+
+async def check_redirects():
+    session = Session(Response(302, "/api/v3/pet/2"), Response(200))
+    assert await follow(session, "GET", BASE + "/pet/1") == 200
+    assert session.requests[1][1] == BASE + "/pet/2"
+
+    # the body is dropped when a POST is redirected with 303
+    session = Session(Response(303, BASE + "/store/order/1"), Response(200))
+    assert await follow(session, "POST", BASE + "/store/order", json={"id": 1}) == 200
+    assert session.requests == [("POST", BASE + "/store/order", True), ("GET", BASE + "/store/order/1", False)]
+
+    for location in ["https://evil.example/steal", "//evil.example/steal", BASE.replace("https://", "http://") + "/pet"]:
+        session = Session(Response(307, location))
+        try:
+            await follow(session, "DELETE", BASE + "/pet/1")
+        except ValueError:
+            pass
+        else:
+            raise AssertionError(f"redirect to {location} was followed")
+        assert len(session.requests) == 1
+
+    session = Session(*[Response(302, "/api/v3/pet/1")] * (server.MAX_REDIRECTS + 1))
+    try:
+        await follow(session, "GET", BASE + "/pet/1")
+    except ValueError:
+        pass
+    else:
+        raise AssertionError("redirect loop was followed")
+
+
+asyncio.run(check_redirects())
+print("ok")
@@ -0,0 +1,44 @@
+"""Loads a generated server.py with its third-party imports stubbed out.
+
+The check_*.py scripts import this module first, so only the standard
+library is needed to run the helpers of a generated server.
+"""
+import importlib.util
+import sys
+import types
+
+
+class Stub:
+    """Accepts any construction, call and attribute access."""
+
+    def __init__(self, *args, **kwargs):
+        pass
+
+    def __call__(self, *args, **kwargs):
+        return Stub()
+
+    def __getattr__(self, name):
+        return Stub()
+
+
+def stub_module(name):
+    module = types.ModuleType(name)
+    module.__getattr__ = lambda attr: Stub
+    sys.modules[name] = module
+    parent, _, child = name.rpartition(".")
+    if parent:
+        setattr(sys.modules[parent], child, module)
+    return module
+
+
+for name in ["aiohttp", "jsonschema", "pydantic", "yarl", "mcp", "mcp.types", "mcp.server", "mcp.server.models", "mcp.server.stdio"]:
+    stub_module(name)
+
+
+def load_server(path):
+    """Imports the server.py at path without running it."""
+    spec = importlib.util.spec_from_file_location("server", path)
+    server = importlib.util.module_from_spec(spec)
+    sys.argv = ["server"]
+    spec.loader.exec_module(server)
+    return server